@@ -0,0 +1,808 @@
+package dqlx
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Node is implemented by every node of the query AST
+type Node interface {
+	astNode()
+}
+
+// DocumentNode represents one or more query blocks with their variable blocks
+type DocumentNode struct {
+	Variables []*EdgeNode
+	Blocks    []*EdgeNode
+}
+
+// EdgeNode represents a query block or a nested edge.
+// Path is the abstract path of the edge relative to its block,
+// it is empty for blocks
+//
+// Example: "friends->posts"
+type EdgeNode struct {
+	Name       string
+	Path       string
+	Alias      string
+	IsRoot     bool
+	IsVariable bool
+	RootFilter *FilterNode
+	Filters    []*FilterNode
+	Pagination Cursor
+	Order      []*OrderNode
+	GroupBy    []string
+	Facets     []*FacetsNode
+	Cascade    *CascadeNode
+	Selections []*SelectionNode
+	Edges      []*EdgeNode
+}
+
+// SelectionNode represents a selected predicate.
+// Computed selections such as count() or val() have a non nil Expression
+//
+// Example: "D as alias:name@en" -> Variable: D, Alias: alias, Predicate: name, Directive: @en
+type SelectionNode struct {
+	Predicate  string
+	Alias      string
+	Variable   string
+	Directive  string
+	Expression DQLizer
+}
+
+// FilterNode represents a filter.
+// Connectives (AND, OR, NOT) have an Operator and nested Filters,
+// functions have a Func, an optional Predicate and their Args
+//
+// Example: eq(age, 20) -> Func: eq, Predicate: age, Args: [20]
+type FilterNode struct {
+	Operator   string
+	Func       string
+	Predicate  string
+	Args       []interface{}
+	Filters    []*FilterNode
+	Expression DQLizer
+}
+
+// OrderNode represents an ordering clause
+type OrderNode struct {
+	Direction  OrderDirection
+	Predicate  string
+	Expression DQLizer
+}
+
+// FacetsNode represents a @facets directive
+type FacetsNode struct {
+	Predicates []string
+	Filters    []*FilterNode
+	Expression DQLizer
+}
+
+// CascadeNode represents a @cascade directive
+type CascadeNode struct {
+	Fields []string
+}
+
+func (*DocumentNode) astNode()  {}
+func (*EdgeNode) astNode()      {}
+func (*SelectionNode) astNode() {}
+func (*FilterNode) astNode()    {}
+func (*OrderNode) astNode()     {}
+func (*FacetsNode) astNode()    {}
+func (*CascadeNode) astNode()   {}
+
+// AST returns a read-only representation of the query
+//
+// Example:
+//   dqlx.Query(...).AST()
+func (builder QueryBuilder) AST() *DocumentNode {
+	return QueriesToAST(builder)
+}
+
+// QueriesToAST returns a read-only representation of 1 or more queries
+//
+// Example:
+//   dqlx.QueriesToAST(query1, query2)
+func QueriesToAST(queries ...QueryBuilder) *DocumentNode {
+	document := &DocumentNode{}
+
+	for _, query := range queries {
+		for _, variable := range query.variables {
+			document.Variables = append(document.Variables, edgeToAST(variable.rootEdge, ""))
+		}
+
+		document.Blocks = append(document.Blocks, edgeToAST(query.rootEdge, ""))
+	}
+
+	return document
+}
+
+// DefinedVariables returns the names of the variables defined in the document
+//
+// Example: { C as var(func: ...) { D as name } } -> [C, D]
+func (document *DocumentNode) DefinedVariables() []string {
+	var variables []string
+
+	Inspect(document, func(node Node) bool {
+		switch cast := node.(type) {
+		case *EdgeNode:
+			if cast.Alias != "" {
+				variables = appendUnique(variables, cast.Alias)
+			}
+		case *SelectionNode:
+			if cast.Variable != "" {
+				variables = appendUnique(variables, cast.Variable)
+			}
+		}
+		return true
+	})
+
+	return variables
+}
+
+// UsedVariables returns the names of the variables referenced in the document
+// such as uid(C) or val(D)
+func (document *DocumentNode) UsedVariables() []string {
+	var variables []string
+
+	Inspect(document, func(node Node) bool {
+		switch cast := node.(type) {
+		case *FilterNode:
+			if isVariableFunc(cast.Func) && cast.Predicate != "" {
+				variables = appendUnique(variables, cast.Predicate)
+			}
+
+			for _, arg := range cast.Args {
+				if expression, ok := arg.(RawExpression); ok {
+					for _, name := range variableReferences(expression.Val) {
+						variables = appendUnique(variables, name)
+					}
+				}
+			}
+		case *SelectionNode:
+			if cast.Expression != nil {
+				if query, _, err := cast.Expression.ToDQL(); err == nil {
+					for _, name := range valReferences(query) {
+						variables = appendUnique(variables, name)
+					}
+				}
+			}
+		case *OrderNode:
+			if cast.Expression != nil {
+				if query, _, err := cast.Expression.ToDQL(); err == nil {
+					for _, name := range valReferences(query) {
+						variables = appendUnique(variables, name)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return variables
+}
+
+// Predicates returns the predicates referenced by the filter and its nested filters
+func (filter *FilterNode) Predicates() []string {
+	var predicates []string
+
+	Inspect(filter, func(node Node) bool {
+		if cast, ok := node.(*FilterNode); ok && cast.Predicate != "" && !isVariableFunc(cast.Func) {
+			predicates = appendUnique(predicates, cast.Predicate)
+		}
+		return true
+	})
+
+	return predicates
+}
+
+// Visitor is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order
+//
+// Example:
+//   dqlx.Walk(visitor, query.AST())
+func Walk(visitor Visitor, node Node) {
+	if visitor = visitor.Visit(node); visitor == nil {
+		return
+	}
+
+	switch cast := node.(type) {
+	case *DocumentNode:
+		for _, edge := range cast.Variables {
+			Walk(visitor, edge)
+		}
+		for _, edge := range cast.Blocks {
+			Walk(visitor, edge)
+		}
+	case *EdgeNode:
+		if cast.RootFilter != nil {
+			Walk(visitor, cast.RootFilter)
+		}
+		for _, filter := range cast.Filters {
+			Walk(visitor, filter)
+		}
+		for _, order := range cast.Order {
+			Walk(visitor, order)
+		}
+		for _, facets := range cast.Facets {
+			Walk(visitor, facets)
+		}
+		if cast.Cascade != nil {
+			Walk(visitor, cast.Cascade)
+		}
+		for _, selection := range cast.Selections {
+			Walk(visitor, selection)
+		}
+		for _, edge := range cast.Edges {
+			Walk(visitor, edge)
+		}
+	case *FacetsNode:
+		for _, filter := range cast.Filters {
+			Walk(visitor, filter)
+		}
+	case *FilterNode:
+		for _, filter := range cast.Filters {
+			Walk(visitor, filter)
+		}
+	}
+
+	visitor.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order calling f for each node.
+// If f returns true, Inspect invokes f for each of the children of the node,
+// followed by a call of f(nil)
+//
+// Example:
+//   dqlx.Inspect(query.AST(), func(node dqlx.Node) bool {
+//     if edge, ok := node.(*dqlx.EdgeNode); ok {
+//       fmt.Println(edge.Path)
+//     }
+//     return true
+//   })
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+func edgeToAST(edge edge, parentPath string) *EdgeNode {
+	edgeNode := &EdgeNode{
+		Name:       edge.RelativeName(),
+		Alias:      edge.Alias,
+		IsRoot:     edge.IsRoot,
+		IsVariable: edge.IsVariable,
+		Pagination: edge.Pagination,
+	}
+
+	if !edge.IsRoot {
+		edgeNode.Path = edgeNode.Name
+
+		if parentPath != "" {
+			edgeNode.Path = EdgePath(parentPath, edgeNode.Name)
+		}
+	}
+
+	if edge.RootFilter != nil {
+		edgeNode.RootFilter = filterToAST(copyFilter(edge.RootFilter))
+	}
+
+	for _, filter := range edge.Filters {
+		edgeNode.Filters = append(edgeNode.Filters, filterToAST(copyFilter(filter)))
+	}
+
+	for _, order := range edge.Order {
+		edgeNode.Order = append(edgeNode.Order, orderToAST(copyFilter(order)))
+	}
+
+	for _, groupBy := range edge.Group {
+		if cast, ok := groupBy.(group); ok {
+			edgeNode.GroupBy = append(edgeNode.GroupBy, cast.Predicate)
+		}
+	}
+
+	for _, facets := range edge.Facets {
+		edgeNode.Facets = append(edgeNode.Facets, facetsToAST(copyFilter(facets)))
+	}
+
+	if cascade, ok := edge.Cascade.(cascadeExpr); ok {
+		edgeNode.Cascade = &CascadeNode{Fields: append([]string(nil), cascade.fields...)}
+	}
+
+	if attributes, ok := edge.Node.Attributes.(nodeAttributes); ok {
+		edgeNode.Selections, edgeNode.Edges = attributesToAST(attributes, edgeNode.Path)
+	}
+
+	for _, nestedEdge := range edge.Node.Edges[edge.Node.ParentName] {
+		nestedRoot := nestedEdge.rootEdge
+		nestedRoot.Node.Edges = edge.Node.Edges
+
+		edgeNode.Edges = append(edgeNode.Edges, edgeToAST(nestedRoot, edgeNode.Path))
+	}
+
+	return edgeNode
+}
+
+func filterToAST(filter DQLizer) *FilterNode {
+	switch cast := filter.(type) {
+	case *FilterFn:
+		return filterToAST(cast.DQLizer)
+	case FilterFn:
+		return filterToAST(cast.DQLizer)
+	case And:
+		return connectiveToAST("AND", cast)
+	case Or:
+		return connectiveToAST("OR", cast)
+	case Not:
+		return connectiveToAST("NOT", cast)
+	case Eq:
+		return filterKVToAST(eqFunc, filterKV(cast), cast)
+	case Le:
+		return filterKVToAST(leFunc, filterKV(cast), cast)
+	case Lt:
+		return filterKVToAST(ltFunc, filterKV(cast), cast)
+	case Ge:
+		return filterKVToAST(geFunc, filterKV(cast), cast)
+	case Gt:
+		return filterKVToAST(gtFunc, filterKV(cast), cast)
+	case AllOfTerms:
+		return filterKVToAST(alloftermsFunc, filterKV(cast), cast)
+	case AnyOfTerms:
+		return filterKVToAST(anyoftermsFunc, filterKV(cast), cast)
+	case Match:
+		return filterKVToAST(matchFunc, filterKV(cast), cast)
+	case AllOfText:
+		return filterKVToAST(alloftextFunc, filterKV(cast), cast)
+	case AnyOfText:
+		return filterKVToAST(anyoftextFunc, filterKV(cast), cast)
+	case Exact:
+		return filterKVToAST(exactFunc, filterKV(cast), cast)
+	case Term:
+		return filterKVToAST(termFunc, filterKV(cast), cast)
+	case FullText:
+		return filterKVToAST(fulltextFunc, filterKV(cast), cast)
+	case UIDIn:
+		return filterKVToAST(uidInFunc, filterKV(cast), cast)
+	case Regexp:
+		patterns := filterKV{}
+		for key, value := range cast {
+			patterns[key] = Expr(value)
+		}
+		return filterKVToAST(regexpFunc, patterns, cast)
+	case SafeRegexp:
+		return filterKVToAST(regexpFunc, filterKV{cast.predicate: cast.String()}, cast)
+	case between:
+		return &FilterNode{
+			Func:       string(betweenFunc),
+			Predicate:  cast.predicate,
+			Args:       []interface{}{cast.from, cast.to},
+			Expression: cast,
+		}
+	case filterExpr:
+		return filterExprToAST(cast)
+	}
+
+	return &FilterNode{Expression: filter}
+}
+
+func filterExprToAST(filter filterExpr) *FilterNode {
+	switch value := filter.value.(type) {
+	case filterKV:
+		return filterKVToAST(filter.funcType, value, filter)
+	case filterExpr:
+		// nested functions such as uid(val(<variable>))
+		return &FilterNode{
+			Func:       string(filter.funcType),
+			Filters:    []*FilterNode{filterExprToAST(value)},
+			Expression: filter,
+		}
+	case RawExpression:
+		// functions such as has(<predicate>) or val(<variable>)
+		predicate, ok := unescapePredicate(value.Val)
+
+		if filter.predicate != "" {
+			predicate, ok = filter.predicate, true
+		}
+
+		if ok {
+			if filter.funcType == typeFunc {
+				return &FilterNode{
+					Func:       string(filter.funcType),
+					Args:       []interface{}{predicate},
+					Expression: filter,
+				}
+			}
+
+			return &FilterNode{
+				Func:       string(filter.funcType),
+				Predicate:  predicate,
+				Expression: filter,
+			}
+		}
+	}
+
+	return &FilterNode{
+		Func:       string(filter.funcType),
+		Args:       []interface{}{filter.value},
+		Expression: filter,
+	}
+}
+
+func filterKVToAST(funcType FuncType, filter filterKV, expression DQLizer) *FilterNode {
+	var filters []*FilterNode
+
+	for _, key := range getSortedKeys(filter) {
+		filters = append(filters, &FilterNode{
+			Func:       string(funcType),
+			Predicate:  key,
+			Args:       []interface{}{filter[key]},
+			Expression: expression,
+		})
+	}
+
+	if len(filters) == 1 {
+		return filters[0]
+	}
+
+	return &FilterNode{
+		Operator:   "AND",
+		Filters:    filters,
+		Expression: expression,
+	}
+}
+
+func connectiveToAST(operator string, filters []DQLizer) *FilterNode {
+	filterNode := &FilterNode{Operator: operator}
+
+	for _, filter := range filters {
+		filterNode.Filters = append(filterNode.Filters, filterToAST(filter))
+	}
+
+	switch operator {
+	case "AND":
+		filterNode.Expression = And(filters)
+	case "OR":
+		filterNode.Expression = Or(filters)
+	case "NOT":
+		filterNode.Expression = Not(filters)
+	}
+
+	return filterNode
+}
+
+func orderToAST(order DQLizer) *OrderNode {
+	cast, ok := order.(orderBy)
+
+	if !ok {
+		return &OrderNode{Expression: order}
+	}
+
+	orderNode := &OrderNode{Direction: cast.Direction}
+
+	switch predicate := cast.Predicate.(type) {
+	case string:
+		orderNode.Predicate = predicate
+	case DQLizer:
+		orderNode.Expression = predicate
+	}
+
+	return orderNode
+}
+
+func facetsToAST(facets DQLizer) *FacetsNode {
+	cast, ok := facets.(facetExpr)
+
+	if !ok {
+		return &FacetsNode{Expression: facets}
+	}
+
+	facetsNode := &FacetsNode{Expression: facets}
+
+	for _, predicate := range cast.Predicates {
+		switch value := predicate.(type) {
+		case string:
+			facetsNode.Predicates = append(facetsNode.Predicates, value)
+		case DQLizer:
+			facetsNode.Filters = append(facetsNode.Filters, filterToAST(value))
+		}
+	}
+
+	return facetsNode
+}
+
+// attributesToAST returns the selections of the attributes, struct and
+// schema type selections are expanded into their selections and edges
+func attributesToAST(attributes nodeAttributes, path string) (selections []*SelectionNode, edges []*EdgeNode) {
+	for _, predicate := range attributes.predicates {
+		var nestedSelections []*SelectionNode
+		var nestedEdges []*EdgeNode
+
+		switch cast := predicate.(type) {
+		case nodeAttributes:
+			nestedSelections, nestedEdges = attributesToAST(cast, path)
+		case fieldsSelection:
+			nestedSelections, nestedEdges = selectedFieldsToAST(cast.selectedFields(), path)
+		default:
+			nestedSelections = selectionsToAST(predicate)
+		}
+
+		selections = append(selections, nestedSelections...)
+		edges = append(edges, nestedEdges...)
+	}
+
+	return selections, edges
+}
+
+func selectedFieldsToAST(fields []selectedField, path string) (selections []*SelectionNode, edges []*EdgeNode) {
+	for _, field := range fields {
+		if !field.isEdge {
+			selection := parseSelection(field.predicate)
+			selection.Alias = field.alias
+
+			if len(field.facets) > 0 {
+				statement, _, _ := Facets(field.facets...).ToDQL()
+				selection.Directive = strings.TrimSpace(selection.Directive + " " + statement)
+			}
+
+			selections = append(selections, selection)
+			continue
+		}
+
+		edgeNode := &EdgeNode{
+			Name: field.predicate,
+			Path: field.predicate,
+		}
+
+		if path != "" {
+			edgeNode.Path = EdgePath(path, field.predicate)
+		}
+
+		if len(field.facets) > 0 {
+			edgeNode.Facets = []*FacetsNode{facetsToAST(Facets(field.facets...))}
+		}
+
+		nested := field.fields
+
+		if len(nested) == 0 {
+			nested = []selectedField{{predicate: "uid"}}
+		}
+
+		edgeNode.Selections, edgeNode.Edges = selectedFieldsToAST(nested, edgeNode.Path)
+		edges = append(edges, edgeNode)
+	}
+
+	return selections, edges
+}
+
+func selectionsToAST(predicate interface{}) []*SelectionNode {
+	switch cast := predicate.(type) {
+	case string:
+		var selections []*SelectionNode
+
+		for _, line := range strings.Split(cast, "\n") {
+			line = Minify(line)
+
+			if line == "" {
+				continue
+			}
+
+			selections = append(selections, parseSelection(line))
+		}
+		return selections
+	case aliasField:
+		selections := selectionsToAST(cast.value)
+
+		for _, selection := range selections {
+			selection.Alias = cast.alias
+		}
+		return selections
+	case as:
+		selections := selectionsToAST(cast.predicate)
+
+		for _, selection := range selections {
+			selection.Variable = cast.variable
+		}
+		return selections
+	case DQLizer:
+		return []*SelectionNode{{Expression: cast}}
+	}
+
+	return nil
+}
+
+func parseSelection(line string) *SelectionNode {
+	selection := &SelectionNode{}
+	parts := strings.Fields(line)
+
+	if len(parts) > 2 && strings.ToLower(parts[1]) == "as" {
+		selection.Variable = parts[0]
+		line = strings.Join(parts[2:], "")
+
+		// a repeated "as" is kept in the predicate, rejected in strict mode
+		for _, part := range parts[2:] {
+			if strings.ToLower(part) == "as" {
+				line = strings.Join(parts[2:], " ")
+			}
+		}
+	}
+
+	predicate, alias, directive := parsePredicate(line)
+
+	selection.Predicate = strings.TrimSpace(predicate)
+	selection.Alias = alias
+	selection.Directive = directive
+
+	return selection
+}
+
+// isVariableFunc determines if the function references a variable
+// rather than a predicate
+func isVariableFunc(funcType string) bool {
+	return funcType == string(valFunc) || funcType == string(uidFunc)
+}
+
+func unescapePredicate(value string) (string, bool) {
+	if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+var variableReferencePattern = regexp.MustCompile(`^<?([A-Za-z_][A-Za-z0-9_]*)>?$`)
+var valReferencePattern = regexp.MustCompile(`val\(<?([A-Za-z_][A-Za-z0-9_]*)>?\)`)
+
+func variableReferences(expression string) []string {
+	matches := variableReferencePattern.FindStringSubmatch(strings.TrimSpace(expression))
+
+	if matches == nil {
+		return valReferences(expression)
+	}
+
+	return []string{matches[1]}
+}
+
+func valReferences(expression string) []string {
+	var references []string
+
+	for _, match := range valReferencePattern.FindAllStringSubmatch(expression, -1) {
+		references = append(references, match[1])
+	}
+
+	return references
+}
+
+// copyFilter copies the maps and slices held by an expression,
+// so that editing the AST never changes the query builder
+func copyFilter(filter DQLizer) DQLizer {
+	switch cast := filter.(type) {
+	case *FilterFn:
+		return &FilterFn{copyFilter(cast.DQLizer)}
+	case FilterFn:
+		return FilterFn{copyFilter(cast.DQLizer)}
+	case And:
+		return And(copyFilters(cast))
+	case Or:
+		return Or(copyFilters(cast))
+	case Not:
+		return Not(copyFilters(cast))
+	case filterExpr:
+		if nested, ok := cast.value.(filterExpr); ok {
+			cast.value = copyFilter(nested)
+		} else {
+			cast.value = copyValue(cast.value)
+		}
+		return cast
+	case functionExpr:
+		cast.args, _ = copyValue(cast.args).([]interface{})
+		return cast
+	case between:
+		cast.from = copyValue(cast.from)
+		cast.to = copyValue(cast.to)
+		return cast
+	case orderBy:
+		if predicate, ok := cast.Predicate.(DQLizer); ok {
+			cast.Predicate = copyFilter(predicate)
+		}
+		return cast
+	case facetExpr:
+		predicates := make([]interface{}, len(cast.Predicates))
+
+		for index, predicate := range cast.Predicates {
+			if expression, ok := predicate.(DQLizer); ok {
+				predicates[index] = copyFilter(expression)
+			} else {
+				predicates[index] = copyValue(predicate)
+			}
+		}
+
+		cast.Predicates = predicates
+		return cast
+	}
+
+	// expressions based on maps such as Eq
+	if copied, ok := copyValue(filter).(DQLizer); ok {
+		return copied
+	}
+
+	return filter
+}
+
+func copyFilters(filters []DQLizer) []DQLizer {
+	copied := make([]DQLizer, len(filters))
+
+	for index, filter := range filters {
+		copied[index] = copyFilter(filter)
+	}
+
+	return copied
+}
+
+// copyValue copies the slices and maps of a value
+func copyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return copyReflectValue(reflect.ValueOf(value)).Interface()
+}
+
+func copyReflectValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		return copyReflectValue(value.Elem())
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		for index := 0; index < value.Len(); index++ {
+			copied.Index(index).Set(copyReflectValue(value.Index(index)))
+		}
+
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iterator := value.MapRange()
+
+		for iterator.Next() {
+			copied.SetMapIndex(iterator.Key(), copyReflectValue(iterator.Value()))
+		}
+
+		return copied
+	}
+
+	return value
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
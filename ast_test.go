@@ -0,0 +1,148 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_AST(t *testing.T) {
+	variable := dql.Variable(dql.EqFn("name", "test")).
+		Edge("film").
+		Edge("film->performance", dql.Select(`
+			D as genre
+		`))
+
+	query := dql.
+		QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+		Select(`
+			uid
+			title:name@en
+		`, dql.Alias("total", dql.Count("actors"))).
+		Variable(variable).
+		Filter(dql.Or{dql.Eq{"rating": 5}, dql.Has("award")}, dql.UID(dql.Val("D"))).
+		OrderDesc("rating").
+		Paginate(dql.Cursor{First: 10}).
+		Cascade().
+		Edge("actors", dql.Select("name"), dql.Between("age", 18, 30), dql.Facets("role")).
+		Edge("actors->rewards", dql.Select("points"))
+
+	document := query.AST()
+
+	require.Len(t, document.Variables, 1)
+	require.True(t, document.Variables[0].IsVariable)
+	require.Equal(t, "film->performance", document.Variables[0].Edges[0].Edges[0].Path)
+
+	require.Len(t, document.Blocks, 1)
+	block := document.Blocks[0]
+
+	require.Equal(t, "bladerunner", block.Name)
+	require.True(t, block.IsRoot)
+	require.Equal(t, dql.Cursor{First: 10}, block.Pagination)
+	require.Equal(t, &dql.CascadeNode{}, block.Cascade)
+
+	require.Equal(t, "eq", block.RootFilter.Func)
+	require.Equal(t, "name@en", block.RootFilter.Predicate)
+	require.Equal(t, []interface{}{"Blade Runner"}, block.RootFilter.Args)
+
+	require.Len(t, block.Filters, 2)
+	require.Equal(t, "OR", block.Filters[0].Operator)
+	require.Equal(t, []string{"rating", "award"}, block.Filters[0].Predicates())
+	require.Equal(t, "uid", block.Filters[1].Func)
+	require.Equal(t, "val", block.Filters[1].Filters[0].Func)
+
+	require.Equal(t, dql.OrderDirectionDesc, block.Order[0].Direction)
+	require.Equal(t, "rating", block.Order[0].Predicate)
+
+	require.Len(t, block.Selections, 3)
+	require.Equal(t, "uid", block.Selections[0].Predicate)
+	require.Equal(t, &dql.SelectionNode{Predicate: "name", Alias: "title", Directive: "@en"}, block.Selections[1])
+	require.Equal(t, "total", block.Selections[2].Alias)
+	require.NotNil(t, block.Selections[2].Expression)
+
+	require.Len(t, block.Edges, 1)
+	actors := block.Edges[0]
+	require.Equal(t, "actors", actors.Path)
+	require.Equal(t, "between", actors.Filters[0].Func)
+	require.Equal(t, []interface{}{18, 30}, actors.Filters[0].Args)
+	require.Equal(t, []string{"role"}, actors.Facets[0].Predicates)
+	require.Equal(t, "actors->rewards", actors.Edges[0].Path)
+
+	require.Equal(t, []string{"D"}, document.DefinedVariables())
+	require.Equal(t, []string{"D"}, document.UsedVariables())
+}
+
+func Test_Query_AST_Inspect(t *testing.T) {
+	query := dql.QueryType("User").
+		Select("name", "email").
+		Filter(dql.Eq{"active": true, "role": "admin"}).
+		Edge("friends", dql.Select("name")).
+		Edge("friends->posts", dql.Select("title", "body"), dql.AllOfTerms{"title": "dgraph"})
+
+	var paths []string
+	var predicates []string
+	var filtered []string
+
+	dql.Inspect(query.AST(), func(node dql.Node) bool {
+		switch cast := node.(type) {
+		case *dql.EdgeNode:
+			paths = append(paths, cast.Path)
+		case *dql.SelectionNode:
+			predicates = append(predicates, cast.Predicate)
+		case *dql.FilterNode:
+			if cast.Func != "" {
+				filtered = append(filtered, cast.Func+":"+cast.Predicate)
+			}
+		}
+		return true
+	})
+
+	require.Equal(t, []string{"", "friends", "friends->posts"}, paths)
+	require.Equal(t, []string{"name", "email", "name", "title", "body"}, predicates)
+	require.Equal(t, []string{"type:", "eq:active", "eq:role", "allofterms:title"}, filtered)
+
+	// skip the children of the edges
+	var visited []string
+	dql.Inspect(query.AST(), func(node dql.Node) bool {
+		if cast, ok := node.(*dql.EdgeNode); ok {
+			visited = append(visited, cast.Name)
+			return false
+		}
+		return true
+	})
+
+	require.Equal(t, []string{"rootQuery"}, visited)
+}
+
+func Test_Query_AST_Is_A_Copy(t *testing.T) {
+	query := dql.Query(dql.UIDFn([]string{"0x1", "0x2"})).
+		Filter(dql.Eq{"tags": []string{"a", "b"}}).
+		Filter(dql.Or{dql.Has("name"), dql.Gt{"age": 18}}).
+		Facets(dql.Eq{"close": true}).
+		Cascade("name", "age").
+		Select("name")
+
+	expected, expectedVariables, err := query.ToDQL()
+	require.NoError(t, err)
+
+	block := query.AST().Blocks[0]
+
+	block.Cascade.Fields[0] = "email"
+	block.RootFilter.Args[0].([]string)[0] = "0x3"
+
+	tags := block.Filters[0]
+	tags.Args[0].([]string)[0] = "c"
+	tags.Expression.(dql.Eq)["tags"] = []string{"d"}
+
+	or := block.Filters[1]
+	or.Expression.(dql.Or)[0] = dql.Has("password")
+	or.Filters[1].Expression.(dql.Gt)["age"] = 99
+
+	block.Facets[0].Filters[0].Expression.(dql.Eq)["close"] = false
+
+	actual, variables, err := query.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Equal(t, expectedVariables, variables)
+}
@@ -0,0 +1,360 @@
+package dqlx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TestingT is the subset of *testing.T used by the DQL assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// NormalizeDQL returns a canonical representation of a DQL document.
+// The values of the variables are inlined, the predicates are unquoted
+// when possible and the operands of AND / OR statements are sorted.
+//
+// Example:
+//   query, variables, _ := dqlx.Query(...).ToDQL()
+//   normalized, err := dqlx.NormalizeDQL(query, variables)
+func NormalizeDQL(query string, variables map[string]string) (string, error) {
+	tokens, err := tokenize(query, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	tokens, err = inlineVariables(tokens, variables)
+
+	if err != nil {
+		return "", err
+	}
+
+	for index, tok := range tokens {
+		if tok.Type == tokenIRI && isPlainName(tok.Value) {
+			tokens[index].Type = tokenName
+		}
+	}
+
+	tokens = normalizeStatements(tokens, false)
+
+	return NewPrettyPrinter().formatTokens(tokens), nil
+}
+
+// CompareDQL compares two DQL documents by meaning rather than formatting.
+// It returns an empty diff when the documents are equivalent
+//
+// Example:
+//   diff, err := dqlx.CompareDQL(expected, nil, query, variables)
+func CompareDQL(expected string, expectedVariables map[string]string, actual string, actualVariables map[string]string) (diff string, err error) {
+	normalizedExpected, err := NormalizeDQL(expected, expectedVariables)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid expected query: %w", err)
+	}
+
+	normalizedActual, err := NormalizeDQL(actual, actualVariables)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid actual query: %w", err)
+	}
+
+	if normalizedExpected == normalizedActual {
+		return "", nil
+	}
+
+	return diffLines(strings.Split(normalizedExpected, "\n"), strings.Split(normalizedActual, "\n")), nil
+}
+
+// EquivalentDQL determines if two DQL documents have the same meaning
+func EquivalentDQL(expected string, expectedVariables map[string]string, actual string, actualVariables map[string]string) (bool, error) {
+	diff, err := CompareDQL(expected, expectedVariables, actual, actualVariables)
+	return diff == "" && err == nil, err
+}
+
+// AssertEquivalentDQL asserts that two DQL documents have the same meaning
+// and reports the differences otherwise
+//
+// Example:
+//   query, variables, err := dqlx.Query(...).ToDQL()
+//   dqlx.AssertEquivalentDQL(t, `{ users(func: eq(name, "alice")) { name } }`, nil, query, variables)
+func AssertEquivalentDQL(t TestingT, expected string, expectedVariables map[string]string, actual string, actualVariables map[string]string) bool {
+	t.Helper()
+
+	diff, err := CompareDQL(expected, expectedVariables, actual, actualVariables)
+
+	if err != nil {
+		t.Errorf("cannot compare DQL documents: %s", err.Error())
+		return false
+	}
+
+	if diff != "" {
+		t.Errorf("DQL documents are not equivalent (- expected, + actual):\n%s", diff)
+		return false
+	}
+
+	return true
+}
+
+// inlineVariables removes the query header and replaces
+// the GraphQL variables with their values as literals
+func inlineVariables(tokens []token, variables map[string]string) ([]token, error) {
+	types := map[string]string{}
+	defaults := map[string]string{}
+
+	header, tokens, err := splitQueryHeader(tokens)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for index, tok := range header {
+		if tok.Type == tokenVariable && index+2 < len(header) && header[index+1].Type == tokenColon {
+			types[tok.Value] = strings.ToLower(header[index+2].Value)
+
+			if index+4 < len(header) && header[index+3].Value == "=" {
+				defaults[tok.Value] = header[index+4].Value
+			}
+		}
+	}
+
+	inlined := make([]token, 0, len(tokens))
+
+	for _, tok := range tokens {
+		if tok.Type != tokenVariable {
+			inlined = append(inlined, tok)
+			continue
+		}
+
+		value, ok := variables[tok.Value]
+
+		if !ok {
+			value, ok = defaults[tok.Value]
+		}
+
+		if !ok {
+			return nil, &ParseError{Pos: tok.Pos, Message: fmt.Sprintf("missing value for variable %s", tok.Value)}
+		}
+
+		literal := token{Type: tokenString, Value: value, Pos: tok.Pos}
+
+		switch types[tok.Value] {
+		case "int", "float":
+			literal.Type = tokenNumber
+		case "bool":
+			literal.Type = tokenName
+		}
+
+		inlined = append(inlined, literal)
+	}
+
+	return inlined, nil
+}
+
+// splitQueryHeader splits a document into the header of a named query,
+// if any, and the body starting at the first '{'. The body keeps the EOF token
+func splitQueryHeader(tokens []token) (header []token, body []token, err error) {
+	if len(tokens) == 0 || !isKeyword(tokens[0], "query") {
+		return nil, tokens, nil
+	}
+
+	for index, tok := range tokens {
+		switch tok.Type {
+		case tokenLeftCurl:
+			return tokens[:index], tokens[index:], nil
+		case tokenEOF:
+			return nil, nil, &ParseError{Pos: tok.Pos, Message: "expected '{' after the query header"}
+		}
+	}
+
+	return nil, nil, &ParseError{Pos: tokens[len(tokens)-1].Pos, Message: "expected '{' after the query header"}
+}
+
+func isPlainName(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if !isNameRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeStatements normalizes the content of every parenthesized group,
+// AND / OR statements are flattened and their operands sorted
+func normalizeStatements(tokens []token, inParens bool) []token {
+	var normalized []token
+
+	for index := 0; index < len(tokens); index++ {
+		if tokens[index].Type != tokenLeftParen {
+			normalized = append(normalized, tokens[index])
+			continue
+		}
+
+		closing := matchingParen(tokens, index)
+		inner := normalizeStatements(tokens[index+1:closing], true)
+
+		normalized = append(normalized, tokens[index])
+		normalized = append(normalized, inner...)
+
+		if closing < len(tokens) {
+			normalized = append(normalized, tokens[closing])
+		}
+
+		index = closing
+	}
+
+	if !inParens {
+		return normalized
+	}
+
+	return normalizeConnective(unwrapParens(normalized), "OR")
+}
+
+func normalizeConnective(tokens []token, connective string) []token {
+	operands := splitOperands(tokens, connective)
+
+	if len(operands) == 1 {
+		if connective == "OR" {
+			return normalizeConnective(tokens, "AND")
+		}
+		return tokens
+	}
+
+	var flattened [][]token
+
+	for _, operand := range operands {
+		if connective == "OR" {
+			operand = normalizeConnective(operand, "AND")
+		}
+
+		unwrapped := unwrapParens(operand)
+		nested := splitOperands(unwrapped, connective)
+
+		switch {
+		case len(nested) > 1:
+			flattened = append(flattened, nested...)
+		case len(splitOperands(unwrapped, "OR")) == 1 && len(splitOperands(unwrapped, "AND")) == 1:
+			flattened = append(flattened, unwrapped)
+		default:
+			flattened = append(flattened, operand)
+		}
+	}
+
+	sort.SliceStable(flattened, func(i, j int) bool {
+		return joinTokens(flattened[i]) < joinTokens(flattened[j])
+	})
+
+	var joined []token
+
+	for index, operand := range flattened {
+		if index > 0 {
+			joined = append(joined, token{Type: tokenName, Value: connective})
+		}
+		joined = append(joined, operand...)
+	}
+
+	return joined
+}
+
+func splitOperands(tokens []token, connective string) [][]token {
+	var operands [][]token
+	nesting := 0
+	start := 0
+
+	for index, tok := range tokens {
+		switch {
+		case tok.Type == tokenLeftParen || tok.Type == tokenLeftSquare:
+			nesting++
+		case tok.Type == tokenRightParen || tok.Type == tokenRightSquare:
+			nesting--
+		case nesting == 0 && isKeyword(tok, connective):
+			operands = append(operands, tokens[start:index])
+			start = index + 1
+		}
+	}
+
+	return append(operands, tokens[start:])
+}
+
+// unwrapParens removes the redundant parentheses wrapping a whole statement
+func unwrapParens(tokens []token) []token {
+	for len(tokens) > 1 && tokens[0].Type == tokenLeftParen && matchingParen(tokens, 0) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+	return tokens
+}
+
+func matchingParen(tokens []token, open int) int {
+	nesting := 0
+
+	for index := open; index < len(tokens); index++ {
+		switch tokens[index].Type {
+		case tokenLeftParen:
+			nesting++
+		case tokenRightParen:
+			nesting--
+
+			if nesting == 0 {
+				return index
+			}
+		}
+	}
+
+	return len(tokens)
+}
+
+func joinTokens(tokens []token) string {
+	parts := make([]string, len(tokens))
+
+	for index, tok := range tokens {
+		parts[index] = tokenText(tok)
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffLines returns a line diff of two documents based on their longest common subsequence
+func diffLines(expected []string, actual []string) string {
+	lengths := make([][]int, len(expected)+1)
+
+	for i := range lengths {
+		lengths[i] = make([]int, len(actual)+1)
+	}
+
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	writer := strings.Builder{}
+	i, j := 0, 0
+
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			writer.WriteString("  " + expected[i] + "\n")
+			i++
+			j++
+		case i < len(expected) && (j == len(actual) || lengths[i+1][j] >= lengths[i][j+1]):
+			writer.WriteString("- " + expected[i] + "\n")
+			i++
+		default:
+			writer.WriteString("+ " + actual[j] + "\n")
+			j++
+		}
+	}
+
+	return strings.TrimRight(writer.String(), "\n")
+}
@@ -0,0 +1,122 @@
+package dqlx_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func Test_Equivalent_DQL(t *testing.T) {
+	query, variables, err := dql.
+		QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+		Fields(`
+			uid
+			name
+		`).
+		Filter(dql.Or{
+			dql.Eq{"name": "actor1"},
+			dql.Eq{"name": "actor2"},
+		}).
+		Edge("authors", dql.Fields(`
+			uid
+		`), dql.Gt{"age": 20}, dql.Lt{"age": 50}, dql.Cursor{First: 10}).
+		ToDQL()
+
+	require.NoError(t, err)
+
+	dql.AssertEquivalentDQL(t, `
+		{
+			bladerunner(func: eq(name@en, "Blade Runner")) @filter(eq(name, "actor2") or eq(name, "actor1")) {
+				uid
+				name
+				authors(first: 10) @filter(lt(age, 50) AND gt(age, 20)) { uid }
+			}
+		}
+	`, nil, query, variables)
+
+	dql.AssertEquivalentDQL(t, `
+		query Q($a: int, $b: string) {
+			bladerunner(func: eq(name@en, $b)) @filter((eq(name, "actor1") OR eq(name, "actor2"))) {
+				uid
+				name
+				authors(first: $a) @filter((gt(age, 20)) AND lt(age, 50)) { uid }
+			}
+		}
+	`, map[string]string{"$a": "10", "$b": "Blade Runner"}, query, variables)
+}
+
+func Test_Equivalent_DQL_Flattens_Connectives(t *testing.T) {
+	equivalent, err := dql.EquivalentDQL(
+		`{ q(func: has(name)) @filter((eq(a, 1) AND eq(b, 2)) AND eq(c, 3)) { uid } }`, nil,
+		`{ q(func: has(name)) @filter(eq(c, 3) AND (eq(b, 2) AND eq(a, 1))) { uid } }`, nil,
+	)
+
+	require.NoError(t, err)
+	require.True(t, equivalent)
+
+	equivalent, err = dql.EquivalentDQL(
+		`{ q(func: has(name)) @filter(eq(a, 1) AND eq(b, 2) OR eq(c, 3)) { uid } }`, nil,
+		`{ q(func: has(name)) @filter(eq(a, 1) AND (eq(b, 2) OR eq(c, 3))) { uid } }`, nil,
+	)
+
+	require.NoError(t, err)
+	require.False(t, equivalent)
+}
+
+func Test_Compare_DQL_Reports_Diff(t *testing.T) {
+	query, variables, err := dql.
+		QueryEdge("bladerunner", dql.EqFn("name", "value")).
+		Fields(`
+			uid
+			name
+		`).
+		ToDQL()
+
+	require.NoError(t, err)
+
+	recorder := &recordingT{}
+	equivalent := dql.AssertEquivalentDQL(recorder, `{ bladerunner(func: eq(name, "other")) { uid name } }`, nil, query, variables)
+
+	require.False(t, equivalent)
+	require.Equal(t, []string{
+		"DQL documents are not equivalent (- expected, + actual):\n" +
+			"  {\n" +
+			"-   bladerunner(func: eq(name, \"other\")) {\n" +
+			"+   bladerunner(func: eq(name, \"value\")) {\n" +
+			"      uid\n" +
+			"      name\n" +
+			"    }\n" +
+			"  }",
+	}, recorder.errors)
+}
+
+func Test_Compare_DQL_Missing_Variable(t *testing.T) {
+	_, err := dql.CompareDQL(`query Q($a: int) { q(func: uid($a)) { uid } }`, nil, `{ q(func: uid(0x1)) { uid } }`, nil)
+	require.EqualError(t, err, "invalid expected query: line 1, column 32: missing value for variable $a")
+}
+
+func Test_Normalize_DQL_Without_Body(t *testing.T) {
+	for _, query := range []string{`query`, `query Q($a: int)`} {
+		_, err := dql.NormalizeDQL(query, nil)
+
+		var parseError *dql.ParseError
+		require.True(t, errors.As(err, &parseError), query)
+		require.Contains(t, err.Error(), "expected '{' after the query header")
+	}
+
+	_, err := dql.CompareDQL(`query`, nil, `{ q(func: uid(0x1)) { uid } }`, nil)
+	require.EqualError(t, err, "invalid expected query: line 1, column 6: expected '{' after the query header")
+}
@@ -11,7 +11,11 @@ import (
 var symbolValuePlaceholder = "??"
 var symbolEdgeTraversal = "->"
 
-// DQLizer implementors are able to define a custom dql statement
+// DQLizer implementors are able to define a custom dql statement,
+// values are returned as arguments replacing the ?? placeholders in order.
+// The output of ToDQL is ambiguous when a raw expression contains ??:
+// the built-in expressions never mistake it for a placeholder within a query,
+// custom DQLizers returning such statements fail to render
 type DQLizer interface {
 	ToDQL() (query string, args []interface{}, err error)
 }
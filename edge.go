@@ -1,8 +1,6 @@
 package dqlx
 
 import (
-	"bytes"
-	"fmt"
 	"strings"
 )
 
@@ -34,20 +32,54 @@ func (edge edge) GetName() string {
 	return edge.RelativeName()
 }
 
+// clone returns a copy of the edge which doesn't share
+// any slice with the original one
+func (edge edge) clone() edge {
+	edge.Filters = cloneDQLizers(edge.Filters)
+	edge.Order = cloneDQLizers(edge.Order)
+	edge.Group = cloneDQLizers(edge.Group)
+	edge.Facets = cloneDQLizers(edge.Facets)
+
+	if attributes, ok := edge.Node.Attributes.(nodeAttributes); ok {
+		edge.Node.Attributes = nodeAttributes{
+			predicates: append([]interface{}{}, attributes.predicates...),
+		}
+	}
+
+	return edge
+}
+
+func cloneDQLizers(parts []DQLizer) []DQLizer {
+	if parts == nil {
+		return nil
+	}
+
+	clonedParts := make([]DQLizer, len(parts))
+	copy(clonedParts, parts)
+	return clonedParts
+}
+
+// ToDQL returns the DQL statement of the edge
 func (edge edge) ToDQL() (query string, args []interface{}, err error) {
-	writer := bytes.Buffer{}
+	return renderToDQL(edge)
+}
+
+func (edge edge) renderDQL(writer *dqlWriter) error {
 	edgeName := edge.RelativeName()
 
 	if edge.Alias != "" {
-		writer.WriteString(fmt.Sprintf("%s as ", edge.Alias))
-	} else {
-		if !(edge.IsRoot && edge.IsVariable) {
-			if edgeName != "" {
-				if strings.HasPrefix(edgeName, "expand(") {
-					writer.WriteString(edgeName)
-				} else {
-					writer.WriteString(fmt.Sprintf("<%s>", edgeName))
-				}
+		writer.WriteString(edge.Alias)
+		writer.WriteString(" as ")
+	}
+
+	if !(edge.IsRoot && edge.IsVariable) {
+		if edgeName != "" {
+			if strings.HasPrefix(edgeName, "expand(") {
+				writer.WriteString(edgeName)
+			} else {
+				writer.WriteString("<")
+				writer.WriteString(edgeName)
+				writer.WriteString(">")
 			}
 		}
 	}
@@ -64,8 +96,8 @@ func (edge edge) ToDQL() (query string, args []interface{}, err error) {
 	}
 
 	if edge.RootFilter != nil {
-		if err := addPart(edge.RootFilter, &writer, &args); err != nil {
-			return "", nil, err
+		if err := writer.writePart(edge.RootFilter); err != nil {
+			return err
 		}
 	}
 
@@ -74,20 +106,18 @@ func (edge edge) ToDQL() (query string, args []interface{}, err error) {
 		if edge.Pagination.WantsPagination() {
 			writer.WriteString(",")
 
-			if err := addPart(edge.Pagination, &writer, &args); err != nil {
-				return "", nil, err
+			if err := writer.writePart(edge.Pagination); err != nil {
+				return err
 			}
 		}
 
 		// Order
 		if len(edge.Order) > 0 {
 			writer.WriteString(",")
-			var statements []string
-			if err := addStatement(edge.Order, &statements, &args); err != nil {
-				return "", nil, err
-			}
 
-			writer.WriteString(strings.Join(statements, ","))
+			if err := writer.writeParts(edge.Order, ","); err != nil {
+				return err
+			}
 		}
 
 		writer.WriteString(")")
@@ -96,8 +126,8 @@ func (edge edge) ToDQL() (query string, args []interface{}, err error) {
 		// Pagination
 		if edge.Pagination.WantsPagination() {
 			writer.WriteString("(")
-			if err := addPart(edge.Pagination, &writer, &args); err != nil {
-				return "", nil, err
+			if err := writer.writePart(edge.Pagination); err != nil {
+				return err
 			}
 			writer.WriteString(")")
 		}
@@ -105,113 +135,90 @@ func (edge edge) ToDQL() (query string, args []interface{}, err error) {
 		// Order
 		if len(edge.Order) > 0 {
 			writer.WriteString("(")
-			var statements []string
-			if err := addStatement(edge.Order, &statements, &args); err != nil {
-				return "", nil, err
+			if err := writer.writeParts(edge.Order, ","); err != nil {
+				return err
 			}
-
-			writer.WriteString(strings.Join(statements, ","))
 			writer.WriteString(")")
 		}
 	}
 
-	if err := edge.addFacets(&writer, &args); err != nil {
-		return "", nil, err
+	if err := edge.addFacets(writer); err != nil {
+		return err
 	}
 
-	if err := edge.addFilters(&writer, &args); err != nil {
-		return "", nil, err
+	if err := edge.addFilters(writer); err != nil {
+		return err
 	}
 
-	if err := edge.addGroupBy(&writer, &args); err != nil {
-		return "", nil, err
+	if err := edge.addGroupBy(writer); err != nil {
+		return err
 	}
 
-	if err := edge.addCascade(&writer, &args); err != nil {
-		return "", nil, err
+	if err := edge.addCascade(writer); err != nil {
+		return err
 	}
 
 	writer.WriteString(" { ")
 
-	if err := edge.addSelection(&writer, &args); err != nil {
-		return "", nil, err
+	if err := edge.addSelection(writer); err != nil {
+		return err
 	}
 
 	writer.WriteString(" }")
 
-	return writer.String(), args, nil
+	return nil
 }
 
-func (edge edge) addFacets(writer *bytes.Buffer, args *[]interface{}) error {
+func (edge edge) addFacets(writer *dqlWriter) error {
 	if len(edge.Facets) == 0 {
 		return nil
 	}
 
 	writer.WriteString(" ")
-
-	var statements []string
-	if err := addStatement(edge.Facets, &statements, args); err != nil {
-		return err
-	}
-
-	writer.WriteString(strings.Join(statements, " "))
-	return nil
+	return writer.writeParts(edge.Facets, " ")
 }
 
-func (edge edge) addFilters(writer *bytes.Buffer, args *[]interface{}) error {
+func (edge edge) addFilters(writer *dqlWriter) error {
 	if len(edge.Filters) == 0 {
 		return nil
 	}
 
 	writer.WriteString(" @filter(")
 
-	var statements []string
-	if err := addStatement(edge.Filters, &statements, args); err != nil {
+	if err := writer.writeParts(edge.Filters, " AND "); err != nil {
 		return err
 	}
 
-	writer.WriteString(strings.Join(statements, " AND "))
-
 	writer.WriteString(")")
 	return nil
 }
 
-func (edge edge) addSelection(writer *bytes.Buffer, args *[]interface{}) error {
-	return addPart(edge.Node, writer, args)
+func (edge edge) addSelection(writer *dqlWriter) error {
+	return writer.writePart(edge.Node)
 }
 
-func (edge edge) addGroupBy(writer *bytes.Buffer, args *[]interface{}) error {
+func (edge edge) addGroupBy(writer *dqlWriter) error {
 	if len(edge.Group) == 0 {
 		return nil
 	}
 
 	writer.WriteString(" @groupby(")
 
-	var statements []string
-	if err := addStatement(edge.Group, &statements, args); err != nil {
+	if err := writer.writeParts(edge.Group, ","); err != nil {
 		return err
 	}
 
-	writer.WriteString(strings.Join(statements, ","))
-
 	writer.WriteString(")")
 	return nil
 }
 
-func (edge edge) addCascade(writer *bytes.Buffer, args *[]interface{}) error {
+func (edge edge) addCascade(writer *dqlWriter) error {
 	if edge.Cascade == nil {
 		return nil
 	}
 
 	writer.WriteString(" ")
-
-	var statements []string
-	if err := addStatement([]DQLizer{edge.Cascade}, &statements, args); err != nil {
-		return err
-	}
-
-	writer.WriteString(strings.Join(statements, " "))
-	return nil
+	return writer.writePart(edge.Cascade)
 }
 
 // EdgePath returns the abstract representation of an edge
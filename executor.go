@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	dgo "github.com/dgraph-io/dgo/v200"
 	"github.com/dgraph-io/dgo/v200/protos/api"
@@ -17,6 +18,8 @@ type OperationExecutor struct {
 
 	readOnly   bool
 	bestEffort bool
+	limits     *QueryLimits
+	allowlist  *QueryAllowlist
 }
 
 // OperationExecutorOptionFn used to modify options of the executor
@@ -64,7 +67,7 @@ func NewDGoExecutor(client *dgo.Dgraph) *OperationExecutor {
 // the transaction will be automatically committed if a custom tnx is not provided.
 // only non-readonly transactions will be committed.
 func (executor OperationExecutor) ExecuteQueries(ctx context.Context, queries ...QueryBuilder) (*Response, error) {
-	if err := executor.ensureClient(); err != nil {
+	if err := executor.checkLimits(queries...); err != nil {
 		return nil, err
 	}
 
@@ -73,6 +76,58 @@ func (executor OperationExecutor) ExecuteQueries(ctx context.Context, queries ..
 		return nil, err
 	}
 
+	if err := executor.checkAllowlist(query); err != nil {
+		return nil, err
+	}
+
+	if err := executor.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	resp, err := executor.query(ctx, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return executor.toResponse(resp, queries...)
+}
+
+// ExecuteDQL executes a DQL query document with the given GraphQL variables,
+// the response holds the result of every query block.
+// When query limits are set the document is parsed with ParseDQL to be checked,
+// documents the parser doesn't support are refused.
+// the transaction will be automatically committed if a custom tnx is not provided.
+// only non-readonly transactions will be committed.
+//
+// Example:
+//   executor.ExecuteDQL(ctx, `query Users($name: string) { ... }`, map[string]string{"$name": "alice"})
+func (executor OperationExecutor) ExecuteDQL(ctx context.Context, query string, variables map[string]string) (*Response, error) {
+	if err := executor.checkDQLLimits(query, variables); err != nil {
+		return nil, err
+	}
+
+	return executor.executeDQL(ctx, query, variables)
+}
+
+// executeDQL executes a DQL document whose limits are already checked
+func (executor OperationExecutor) executeDQL(ctx context.Context, query string, variables map[string]string) (*Response, error) {
+	if err := executor.checkAllowlist(query); err != nil {
+		return nil, err
+	}
+
+	if err := executor.ensureClient(); err != nil {
+		return nil, err
+	}
+
+	resp, err := executor.query(ctx, query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Raw: resp}, nil
+}
+
+func (executor OperationExecutor) query(ctx context.Context, query string, variables map[string]string) (*api.Response, error) {
 	tx := executor.getTnx()
 
 	defer tx.Discard(ctx)
@@ -91,7 +146,7 @@ func (executor OperationExecutor) ExecuteQueries(ctx context.Context, queries ..
 		}
 	}
 
-	return executor.toResponse(resp, queries...)
+	return resp, nil
 }
 
 // ExecuteMutations executes one ore more mutations.
@@ -132,11 +187,17 @@ func (executor OperationExecutor) ExecuteMutations(ctx context.Context, mutation
 		mutationRequests = append(mutationRequests, mutationRequest)
 	}
 
+	if err := executor.checkLimits(queries...); err != nil {
+		return nil, err
+	}
+
 	query, variables, err := QueriesToDQL(queries...)
 
 	if IsEmptyQuery(query) {
 		query = ""
 		variables = nil
+	} else if err := executor.checkAllowlist(query); err != nil {
+		return nil, err
 	}
 
 	request := &api.Request{
@@ -161,6 +222,36 @@ func (executor OperationExecutor) ExecuteMutations(ctx context.Context, mutation
 	return executor.toResponse(resp, queries...)
 }
 
+func (executor OperationExecutor) checkLimits(queries ...QueryBuilder) error {
+	if executor.limits == nil {
+		return nil
+	}
+
+	return executor.limits.Check(queries...)
+}
+
+func (executor OperationExecutor) checkDQLLimits(query string, variables map[string]string) error {
+	if executor.limits == nil {
+		return nil
+	}
+
+	document, err := ParseDQL(query, variables)
+
+	if err != nil {
+		return fmt.Errorf("cannot check the query limits: %w", err)
+	}
+
+	return executor.checkLimits(document.Queries...)
+}
+
+func (executor OperationExecutor) checkAllowlist(query string) error {
+	if executor.allowlist == nil {
+		return nil
+	}
+
+	return executor.allowlist.check(query)
+}
+
 func (executor OperationExecutor) toResponse(resp *api.Response, queries ...QueryBuilder) (*Response, error) {
 	var dataPathKey string
 
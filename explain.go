@@ -0,0 +1,103 @@
+package dqlx
+
+import (
+	"regexp"
+	"strings"
+)
+
+// explainHeader marks the documents returned by Explain
+const explainHeader = "# dqlx explain: values are inlined for debugging, don't execute this query in production"
+
+var regexpLiteralPattern = regexp.MustCompile(`^/.*/[a-z]*$`)
+
+// Explain returns a readable DQL document with the values of the variables inlined,
+// quoted according to their type. The document can be copied into Ratel or logged
+// when a query fails, it must not be executed in production: use the variables instead
+//
+// Example:
+//   query, variables, _ := dqlx.Query(...).ToDQL()
+//   explained, err := dqlx.Explain(query, variables)
+func Explain(query string, variables map[string]string) (string, error) {
+	tokens, err := tokenize(query, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	tokens, err = inlineVariables(tokens, variables)
+
+	if err != nil {
+		return "", err
+	}
+
+	for index, tok := range tokens {
+		if tok.Type != tokenString {
+			continue
+		}
+
+		function, argument := enclosingFunction(tokens, index)
+
+		switch {
+		case function == string(regexpFunc) && argument == 1 && regexpLiteralPattern.MatchString(tok.Value):
+			tokens[index].Type = tokenRegex
+		case (function == string(uidFunc) || function == string(uidInFunc)) && uidPattern.MatchString(tok.Value):
+			tokens[index].Type = tokenName
+		}
+	}
+
+	return explainHeader + "\n" + NewPrettyPrinter().formatTokens(tokens), nil
+}
+
+// Explain returns the DQL document of the query with the values inlined,
+// for debugging only
+//
+// Example:
+//   explained, err := dqlx.Query(...).Explain()
+func (builder QueryBuilder) Explain() (string, error) {
+	query, variables, err := builder.ToDQL()
+
+	if err != nil {
+		return "", err
+	}
+
+	return Explain(query, variables)
+}
+
+// enclosingFunction returns the name of the function called with the token
+// and the position of the token within the arguments. Values of lists
+// are considered to be the argument holding the list
+func enclosingFunction(tokens []token, index int) (function string, argument int) {
+	depth := 0
+
+	for position := index - 1; position >= 0; position-- {
+		switch tokens[position].Type {
+		case tokenRightParen, tokenRightSquare:
+			depth++
+		case tokenLeftSquare:
+			if depth > 0 {
+				depth--
+			} else {
+				argument = 0
+			}
+		case tokenComma:
+			if depth == 0 {
+				argument++
+			}
+		case tokenLeftParen:
+			if depth > 0 {
+				depth--
+				continue
+			}
+
+			if position > 0 && tokens[position-1].Type == tokenName {
+				return strings.ToLower(tokens[position-1].Value), argument
+			}
+
+			return "", argument
+		case tokenLeftCurl, tokenRightCurl:
+			return "", argument
+		}
+	}
+
+	return "", argument
+}
@@ -0,0 +1,61 @@
+package dqlx_test
+
+import (
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	regexp, err := dql.NewRegexp("name", "/^Steven/i")
+	require.NoError(t, err)
+
+	explained, err := dql.Query(dql.EqFn("name", `Say "hi" \ now`)).
+		Filter(
+			dql.Ge{"born": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+			dql.Eq{"tags": []string{"a", `b"c`}},
+			dql.Eq{"active": true},
+			regexp,
+			dql.UIDFn("0x1"),
+		).
+		Paginate(dql.Cursor{First: 10}).
+		Select(`
+			uid
+			name
+		`).
+		Explain()
+
+	require.NoError(t, err)
+	require.Equal(t, `# dqlx explain: values are inlined for debugging, don't execute this query in production
+{
+  <rootQuery>(func: eq(<name>, "Say \"hi\" \\ now"), first: 10) @filter(ge(<born>, "2020-01-02T03:04:05Z") AND eq(<tags>, ["a", "b\"c"]) AND eq(<active>, true) AND regexp(<name>, /^Steven/i) AND uid(0x1)) {
+    <uid>
+    <name>
+  }
+}`, explained)
+}
+
+func TestExplainDQL(t *testing.T) {
+	explained, err := dql.Explain(`query Q($0: int, $1: string = "fallback") { q(func: uid_in(friend, $2)) @filter(gt(age, $0) AND eq(name, $1)) { uid } }`, map[string]string{
+		"$0": "18",
+		"$2": "0x2a",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, `# dqlx explain: values are inlined for debugging, don't execute this query in production
+{
+  q(func: uid_in(friend, 0x2a)) @filter(gt(age, 18) AND eq(name, "fallback")) {
+    uid
+  }
+}`, explained)
+
+	_, err = dql.Explain(`query Q($0: int) { q(func: has(name)) @filter(gt(age, $0)) { uid } }`, nil)
+	require.EqualError(t, err, "line 1, column 55: missing value for variable $0")
+}
+
+func TestExplainWithoutBody(t *testing.T) {
+	_, err := dql.Explain(`query`, nil)
+	require.EqualError(t, err, "line 1, column 6: expected '{' after the query header")
+}
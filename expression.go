@@ -1,7 +1,6 @@
 package dqlx
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"reflect"
@@ -45,59 +44,97 @@ type FilterFn struct {
 type filterExpr struct {
 	funcType FuncType
 	value    interface{}
+	// predicate the name given to functions such as has(predicate),
+	// before escaping. Used to validate the name in strict mode
+	predicate string
 }
 
 func (filter filterExpr) ToDQL() (query string, args []interface{}, err error) {
-	var placeholder string
+	return renderToDQL(filter)
+}
 
+func (filter filterExpr) renderDQL(writer *dqlWriter) error {
 	switch castValue := filter.value.(type) {
 	case filterKV:
-		return castValue.toDQL(filter.funcType)
+		return castValue.renderDQL(writer, filter.funcType)
 	case filterExpr:
-		innerFn, innerArgs, err := castValue.ToDQL()
+		writer.WriteString(string(filter.funcType))
+		writer.WriteString("(")
 
-		if err != nil {
-			return "", nil, err
+		if err := castValue.renderDQL(writer); err != nil {
+			return err
 		}
-
-		placeholder = innerFn
-		args = append(args, innerArgs...)
 	default:
-		valuePlaceholder, valueArgs, err := parseValue(castValue)
+		writer.WriteString(string(filter.funcType))
+		writer.WriteString("(")
 
-		if err != nil {
-			return "", nil, err
+		if err := writer.writeValue(castValue); err != nil {
+			return err
 		}
-
-		placeholder = valuePlaceholder
-		args = append(args, valueArgs...)
 	}
 
-	return fmt.Sprintf("%s(%s)", filter.funcType, placeholder), args, nil
+	writer.WriteString(")")
+	return nil
 }
 
 type filterKV map[string]interface{}
 
-func (filter filterKV) toDQL(funcType FuncType) (query string, args []interface{}, err error) {
-	var expressions []string
-	sortedKeys := getSortedKeys(filter)
-
-	for _, key := range sortedKeys {
-		value := filter[key]
+func (filter filterKV) renderDQL(writer *dqlWriter, funcType FuncType) error {
+	for index, key := range getSortedKeys(filter) {
+		if index > 0 {
+			writer.WriteString(" AND ")
+		}
 
-		placeholder, fnArgs, err := parseValue(value)
+		writer.WriteString(string(funcType))
+		writer.WriteString("(")
+		writer.WriteString(EscapePredicate(key))
+		writer.WriteString(",")
 
-		if err != nil {
-			return "", nil, err
+		if err := writer.writeValue(filter[key]); err != nil {
+			return err
 		}
 
-		fnStatement := fmt.Sprintf("%s(%s,%s)", funcType, EscapePredicate(key), placeholder)
+		writer.WriteString(")")
+	}
 
-		expressions = append(expressions, fnStatement)
-		args = append(args, fnArgs...)
+	return nil
+}
+
+// newFilterKV returns the expression of a function
+// applied to predicates and values, such as Eq or Gt
+func newFilterKV(funcType FuncType, values filterKV) (DQLizer, bool) {
+	switch funcType {
+	case eqFunc:
+		return Eq(values), true
+	case leFunc:
+		return Le(values), true
+	case ltFunc:
+		return Lt(values), true
+	case geFunc:
+		return Ge(values), true
+	case gtFunc:
+		return Gt(values), true
+	case alloftermsFunc:
+		return AllOfTerms(values), true
+	case anyoftermsFunc:
+		return AnyOfTerms(values), true
+	case matchFunc:
+		return Match(values), true
+	case alloftextFunc:
+		return AllOfText(values), true
+	case anyoftextFunc:
+		return AnyOfText(values), true
+	case exactFunc:
+		return Exact(values), true
+	case termFunc:
+		return Term(values), true
+	case fulltextFunc:
+		return FullText(values), true
+	case uidInFunc:
+		return UIDIn(values), true
 	}
 
-	return strings.Join(expressions, " AND "), args, nil
+	return nil, false
 }
 
 // Or represents a OR conjunction statement
@@ -111,25 +148,53 @@ type And conjunction
 type conjunction []DQLizer
 
 func (connector conjunction) join(separator string) (query string, args []interface{}, err error) {
-	if len(connector) == 0 {
-		return "", []interface{}{}, nil
-	}
+	return renderToDQL(joinedConjunction{connector, separator})
+}
+
+type joinedConjunction struct {
+	parts     conjunction
+	separator string
+}
+
+func (joined joinedConjunction) renderDQL(writer *dqlWriter) error {
+	return joined.parts.render(writer, joined.separator)
+}
+
+// render writes the non empty parts joined by the separator, within parenthesis
+func (connector conjunction) render(writer *dqlWriter, separator string) error {
+	start := writer.mark()
+	writer.WriteString("(")
+
+	written := 0
 
-	var parts []string
 	for _, part := range connector {
-		dqlPart, partArgs, err := part.ToDQL()
-		if err != nil {
-			return "", nil, err
+		mark := writer.mark()
+
+		if written > 0 {
+			writer.WriteString(separator)
+		}
+
+		partStart := writer.mark()
+
+		if err := writer.writePart(part); err != nil {
+			return err
 		}
-		if dqlPart != "" {
-			parts = append(parts, dqlPart)
-			args = append(args, partArgs...)
+
+		if !writer.wroteSince(partStart) {
+			writer.reset(mark)
+			continue
 		}
+
+		written++
 	}
-	if len(parts) > 0 {
-		query = fmt.Sprintf("(%s)", strings.Join(parts, separator))
+
+	if written == 0 {
+		writer.reset(start)
+		return nil
 	}
-	return
+
+	writer.WriteString(")")
+	return nil
 }
 
 // ToDQL returns the DQL statement for the 'or' expression
@@ -137,11 +202,45 @@ func (or Or) ToDQL() (query string, args []interface{}, err error) {
 	return conjunction(or).join(" OR ")
 }
 
+func (or Or) renderDQL(writer *dqlWriter) error {
+	return conjunction(or).render(writer, " OR ")
+}
+
 // ToDQL returns the DQL statement for the 'and' expression
 func (and And) ToDQL() (query string, args []interface{}, err error) {
 	return conjunction(and).join(" AND ")
 }
 
+func (and And) renderDQL(writer *dqlWriter) error {
+	return conjunction(and).render(writer, " AND ")
+}
+
+// Not represents a NOT statement, multiple statements are joined with AND
+// Example: dqlx.Not{ dql.Eq{} }
+type Not conjunction
+
+// ToDQL returns the DQL statement for the 'not' expression
+func (not Not) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(not)
+}
+
+func (not Not) renderDQL(writer *dqlWriter) error {
+	mark := writer.mark()
+	writer.WriteString("NOT ")
+
+	partsStart := writer.mark()
+
+	if err := conjunction(not).render(writer, " AND "); err != nil {
+		return err
+	}
+
+	if !writer.wroteSince(partsStart) {
+		writer.reset(mark)
+	}
+
+	return nil
+}
+
 // Eq syntactic sugar for the Eq expression,
 // Expression: eq(predicate, value)
 // Example: dql.Eq{"predicate": "value"}
@@ -251,8 +350,9 @@ func GtFn(predicate string, value interface{}) *FilterFn {
 // Expression: has(predicate)
 func HasFn(predicate string) *FilterFn {
 	expression := filterExpr{
-		funcType: hasFunc,
-		value:    Predicate(predicate),
+		funcType:  hasFunc,
+		value:     Predicate(predicate),
+		predicate: predicate,
 	}
 	return &FilterFn{expression}
 }
@@ -267,8 +367,9 @@ func Has(predicate string) DQLizer {
 // Expression: type(predicate)
 func TypeFn(predicate string) *FilterFn {
 	expression := filterExpr{
-		funcType: typeFunc,
-		value:    Predicate(predicate),
+		funcType:  typeFunc,
+		value:     Predicate(predicate),
+		predicate: predicate,
 	}
 	return &FilterFn{expression}
 }
@@ -324,6 +425,7 @@ func AnyOfTermsFn(predicate string, value interface{}) *FilterFn {
 // Regexp syntactic sugar for the Regexp expression,
 // Expression: regexp(predicate, /pattern/)
 // Example: dql.Regexp{"predicate": /pattern/}
+// The pattern is inlined as is, use NewRegexp for untrusted patterns
 type Regexp map[string]string
 
 // ToDQL returns the DQL statement for the 'regexp' expression
@@ -528,23 +630,27 @@ func Between(predicate string, from interface{}, to interface{}) DQLizer {
 
 // ToDQL returns the DQL statement for the 'between' expression
 func (between between) ToDQL() (query string, args []interface{}, err error) {
-	placeholderFrom, argsFrom, err := parseValue(between.from)
+	return renderToDQL(between)
+}
 
-	if err != nil {
-		return "", nil, err
-	}
+func (between between) renderDQL(writer *dqlWriter) error {
+	writer.WriteString(string(betweenFunc))
+	writer.WriteString("(")
+	writer.WriteString(EscapePredicate(between.predicate))
+	writer.WriteString(",")
 
-	args = append(args, argsFrom...)
+	if err := writer.writeValue(between.from); err != nil {
+		return err
+	}
 
-	placeholderTo, argsTo, err := parseValue(between.to)
+	writer.WriteString(",")
 
-	if err != nil {
-		return "", nil, err
+	if err := writer.writeValue(between.to); err != nil {
+		return err
 	}
 
-	args = append(args, argsTo...)
-
-	return fmt.Sprintf("%s(%s,%s,%s)", betweenFunc, EscapePredicate(between.predicate), placeholderFrom, placeholderTo), args, nil
+	writer.WriteString(")")
+	return nil
 }
 
 // RawExpression represents a raw expression
@@ -558,7 +664,14 @@ func (rawExpression RawExpression) ToDQL() (query string, args []interface{}, er
 	return rawExpression.Val, nil, nil
 }
 
-// Expr returns a RawExpression
+// renderDQL writes the expression as it is, it never binds values
+func (rawExpression RawExpression) renderDQL(writer *dqlWriter) error {
+	writer.WriteString(rawExpression.Val)
+	return nil
+}
+
+// Expr returns a RawExpression,
+// the value is written as it is and never bound
 func Expr(value string) RawExpression {
 	return RawExpression{value}
 }
@@ -572,8 +685,9 @@ func Predicate(predicate string) RawExpression {
 // Expression: val(predicate)
 func Val(ref string) filterExpr {
 	return filterExpr{
-		funcType: valFunc,
-		value:    Predicate(ref),
+		funcType:  valFunc,
+		value:     Predicate(ref),
+		predicate: ref,
 	}
 }
 
@@ -627,23 +741,30 @@ func (p Cursor) WantsPagination() bool {
 
 // ToDQL returns the DQL statement for the 'pagination' expression
 func (p Cursor) ToDQL() (query string, args []interface{}, err error) {
-	var paginationExpressions []string
+	return renderToDQL(p)
+}
+
+func (p Cursor) renderDQL(writer *dqlWriter) error {
+	separator := ""
+
 	if p.First != 0 {
-		paginationExpressions = append(paginationExpressions, "first:??")
-		args = append(args, p.First)
+		writer.WriteString("first:")
+		writer.bind(p.First)
+		separator = ","
 	}
 
 	if p.Offset != 0 {
-		paginationExpressions = append(paginationExpressions, "offset:??")
-		args = append(args, p.Offset)
+		writer.WriteString(separator + "offset:")
+		writer.bind(p.Offset)
+		separator = ","
 	}
 
 	if p.After != "" {
-		paginationExpressions = append(paginationExpressions, "after:??")
-		args = append(args, p.After)
+		writer.WriteString(separator + "after:")
+		writer.bind(p.After)
 	}
 
-	return strings.Join(paginationExpressions, ","), args, nil
+	return nil
 }
 
 // OrderDirection represent an order direction
@@ -741,29 +862,37 @@ type facetExpr struct {
 
 // ToDQL returns the DQL statement for the 'facets' expression
 func (facet facetExpr) ToDQL() (query string, args []interface{}, err error) {
-	var predicates []string
-	for _, predicate := range facet.Predicates {
+	return renderToDQL(facet)
+}
+
+func (facet facetExpr) renderDQL(writer *dqlWriter) error {
+	writer.WriteString("@facets")
+
+	if len(facet.Predicates) == 0 {
+		return nil
+	}
+
+	writer.WriteString("(")
+
+	for index, predicate := range facet.Predicates {
+		if index > 0 {
+			writer.WriteString(",")
+		}
 
 		switch predicateCast := predicate.(type) {
 		case DQLizer:
-			if err := addStatement([]DQLizer{predicateCast}, &predicates, &args); err != nil {
-				return "", nil, err
+			if err := writer.writePart(predicateCast); err != nil {
+				return err
 			}
 		case string:
-			predicates = append(predicates, EscapePredicate(predicateCast))
+			writer.WriteString(EscapePredicate(predicateCast))
 		default:
-			return "", nil, fmt.Errorf("facets accepts only DQlizers or string as value, given %v", predicateCast)
+			return fmt.Errorf("facets accepts only DQlizers or string as value, given %v", predicateCast)
 		}
 	}
 
-	writer := bytes.Buffer{}
-	writer.WriteString("@facets")
-
-	if len(predicates) > 0 {
-		writer.WriteString(fmt.Sprintf("(%s)", strings.Join(predicates, ",")))
-	}
-
-	return writer.String(), args, nil
+	writer.WriteString(")")
+	return nil
 }
 
 // Facets returns the expression for representing facets
@@ -771,46 +900,6 @@ func Facets(predicates ...interface{}) DQLizer {
 	return facetExpr{Predicates: predicates}
 }
 
-func parseValue(value interface{}) (valuePlaceholder string, args []interface{}, err error) {
-	if isListType(value) {
-		var listValue []interface{}
-
-		listValue, err = toInterfaceSlice(value)
-
-		if err != nil {
-			return "", nil, err
-		}
-
-		placeholders := make([]string, len(listValue))
-		for index, value := range listValue {
-			placeholders[index] = symbolValuePlaceholder
-			args = append(args, value)
-		}
-
-		valuePlaceholder = fmt.Sprintf("[%s]", strings.Join(placeholders, ","))
-		return
-	}
-
-	switch castType := value.(type) {
-	case RawExpression:
-		valuePlaceholder = fmt.Sprintf("%s", castType.Val)
-	default:
-		args = append(args, value)
-		valuePlaceholder = symbolValuePlaceholder
-	}
-
-	return
-}
-
-func getSortedVariables(exp map[int]interface{}) []int {
-	sortedKeys := make([]int, 0, len(exp))
-	for k := range exp {
-		sortedKeys = append(sortedKeys, k)
-	}
-	sort.Ints(sortedKeys)
-	return sortedKeys
-}
-
 func getSortedKeys(exp map[string]interface{}) []string {
 	sortedKeys := make([]string, 0, len(exp))
 	for k := range exp {
@@ -125,6 +125,29 @@ func TestType(t *testing.T) {
 	})
 }
 
+func TestNot(t *testing.T) {
+	t.Run("single statement", func(t *testing.T) {
+		query, args, err := dql.Not{dql.Eq{"field1": "value1"}}.ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"value1"}, args)
+		require.Equal(t, "NOT (eq(<field1>,??))", query)
+	})
+
+	t.Run("multiple statements", func(t *testing.T) {
+		query, args, err := dql.Not{dql.Eq{"field1": "value1"}, dql.Has("field2")}.ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"value1"}, args)
+		require.Equal(t, "NOT (eq(<field1>,??) AND has(<field2>))", query)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		query, args, err := dql.Not{}.ToDQL()
+		require.NoError(t, err)
+		require.Len(t, args, 0)
+		require.Equal(t, "", query)
+	})
+}
+
 func TestAllOfTerms(t *testing.T) {
 	t.Run("allOfTerms", func(t *testing.T) {
 		query, args, err := dql.AllOfTerms{
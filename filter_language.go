@@ -0,0 +1,491 @@
+package dqlx
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FilterLanguage compiles textual filters into filter expressions.
+//
+// Grammar:
+//   filter     = or
+//   or         = and { "OR" and }
+//   and        = not { "AND" not }
+//   not        = "NOT" not | "(" filter ")" | function | comparison
+//   comparison = predicate operator value | predicate "IN" list
+//   operator   = "=" | "!=" | ">" | ">=" | "<" | "<="
+//              | "~" (glob pattern, * matches any text and ? a single character)
+//   function   = has(predicate) | type(name)
+//              | allofterms(predicate, string) | anyofterms(predicate, string)
+//              | alloftext(predicate, string) | anyoftext(predicate, string)
+//              | regexp(predicate, /pattern/flags) | between(predicate, value, value)
+//   value      = string | number | true | false | list
+//   list       = "[" [ value { "," value } ] "]"
+//
+// Keywords are case insensitive. Regular expressions are validated,
+// the only supported flag is i, and patterns are sent as GraphQL variables.
+type FilterLanguage struct {
+	predicates map[string]bool
+	functions  map[string]bool
+}
+
+// FilterLanguageOptionFn used to modify options of the filter language
+type FilterLanguageOptionFn func(language *FilterLanguage)
+
+// WithAllowedPredicates restricts the predicates usable in a filter,
+// language tags are allowed on the listed predicates
+func WithAllowedPredicates(predicates ...string) FilterLanguageOptionFn {
+	return func(language *FilterLanguage) {
+		language.predicates = map[string]bool{}
+
+		for _, predicate := range predicates {
+			language.predicates[predicate] = true
+		}
+	}
+}
+
+// WithAllowedFunctions restricts the functions usable in a filter.
+// Comparison operators are compiled into the eq, gt, ge, lt, le functions,
+// the ~ operator into the regexp function
+func WithAllowedFunctions(functions ...string) FilterLanguageOptionFn {
+	return func(language *FilterLanguage) {
+		language.functions = map[string]bool{}
+
+		for _, function := range functions {
+			language.functions[strings.ToLower(function)] = true
+		}
+	}
+}
+
+var filterLanguageFunctions = map[FuncType]bool{
+	eqFunc:         true,
+	gtFunc:         true,
+	geFunc:         true,
+	ltFunc:         true,
+	leFunc:         true,
+	regexpFunc:     true,
+	hasFunc:        true,
+	typeFunc:       true,
+	alloftermsFunc: true,
+	anyoftermsFunc: true,
+	alloftextFunc:  true,
+	anyoftextFunc:  true,
+	betweenFunc:    true,
+}
+
+var comparisonOperators = map[string]FuncType{
+	"=":  eqFunc,
+	"==": eqFunc,
+	"!=": eqFunc,
+	">":  gtFunc,
+	">=": geFunc,
+	"<":  ltFunc,
+	"<=": leFunc,
+	"~":  regexpFunc,
+}
+
+// NewFilterLanguage creates a new FilterLanguage,
+// by default every predicate and function is allowed
+func NewFilterLanguage(options ...FilterLanguageOptionFn) FilterLanguage {
+	language := FilterLanguage{}
+
+	for _, option := range options {
+		option(&language)
+	}
+
+	return language
+}
+
+// ParseFilter compiles a textual filter into a filter expression
+//
+// Example:
+//   filter, err := dqlx.ParseFilter(`age > 5 AND (name ~ "ali*" OR has(email))`,
+//     dqlx.WithAllowedPredicates("age", "name", "email"),
+//   )
+//   dqlx.Query(...).Filter(filter)
+func ParseFilter(input string, options ...FilterLanguageOptionFn) (DQLizer, error) {
+	return NewFilterLanguage(options...).Parse(input)
+}
+
+// Parse compiles a textual filter into a filter expression,
+// errors are returned as *ParseError
+func (language FilterLanguage) Parse(input string) (DQLizer, error) {
+	tokens, err := tokenize(input, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &filterParser{
+		dqlParser: &dqlParser{tokens: splitGlobOperators(tokens)},
+		language:  language,
+	}
+
+	if parser.is(tokenEOF) {
+		return nil, parser.errorf(parser.peek(), "empty filter")
+	}
+
+	filter, err := parser.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parser.expect(tokenEOF, "AND, OR or end of input"); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// splitGlobOperators splits the ~ operator from the predicate
+// as ~ is also valid within predicate names
+func splitGlobOperators(tokens []token) []token {
+	var split []token
+
+	for _, tok := range tokens {
+		if tok.Type == tokenName && len(tok.Value) > 1 && strings.HasSuffix(tok.Value, "~") {
+			predicate := tok
+			predicate.Value = strings.TrimSuffix(tok.Value, "~")
+
+			operator := tok
+			operator.Type = tokenOperator
+			operator.Value = "~"
+			operator.Pos.Offset += len(predicate.Value)
+			operator.Pos.Column += len([]rune(predicate.Value))
+
+			split = append(split, predicate, operator)
+			continue
+		}
+
+		if tok.Type == tokenName && tok.Value == "~" {
+			tok.Type = tokenOperator
+		}
+
+		split = append(split, tok)
+	}
+
+	return split
+}
+
+type filterParser struct {
+	*dqlParser
+	language FilterLanguage
+}
+
+func (parser *filterParser) parseOr() (DQLizer, error) {
+	return parser.parseConnective("or", func() (DQLizer, error) {
+		return parser.parseConnective("and", parser.parseNotFilter)
+	})
+}
+
+func (parser *filterParser) parseNotFilter() (DQLizer, error) {
+	tok := parser.peek()
+
+	switch {
+	case isKeyword(tok, "not"):
+		parser.next()
+		operand, err := parser.parseNotFilter()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{operand}, nil
+	case tok.Type == tokenLeftParen:
+		parser.next()
+		filter, err := parser.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return filter, nil
+	case tok.Type == tokenName && parser.peekAt(1).Type == tokenLeftParen:
+		return parser.parseFilterFunction()
+	}
+
+	return parser.parseComparison()
+}
+
+func (parser *filterParser) parseComparison() (DQLizer, error) {
+	predicate, err := parser.parseAllowedPredicate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	operatorToken := parser.next()
+
+	if isKeyword(operatorToken, "in") {
+		if err := parser.checkFunction(operatorToken, eqFunc); err != nil {
+			return nil, err
+		}
+
+		if !parser.is(tokenLeftSquare) {
+			return nil, parser.unexpected(parser.peek(), "a list")
+		}
+
+		values, err := parser.parseLiteral()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Eq{predicate: values}, nil
+	}
+
+	funcType, ok := comparisonOperators[operatorToken.Value]
+
+	if operatorToken.Type != tokenOperator || !ok {
+		return nil, parser.unexpected(operatorToken, "a comparison operator")
+	}
+
+	if err := parser.checkFunction(operatorToken, funcType); err != nil {
+		return nil, err
+	}
+
+	valueToken := parser.peek()
+	value, err := parser.parseLiteral()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch operatorToken.Value {
+	case "~":
+		pattern, ok := value.(string)
+
+		if !ok {
+			return nil, parser.errorf(valueToken, "the ~ operator expects a string pattern")
+		}
+
+		return parser.newRegexp(valueToken, predicate, globToRegexp(pattern))
+	case "!=":
+		return Not{Eq{predicate: value}}, nil
+	}
+
+	filter, _ := newFilterKV(funcType, filterKV{predicate: value})
+	return filter, nil
+}
+
+func (parser *filterParser) parseFilterFunction() (DQLizer, error) {
+	nameToken := parser.next()
+	funcType := FuncType(strings.ToLower(nameToken.Value))
+
+	if !filterLanguageFunctions[funcType] {
+		return nil, parser.errorf(nameToken, "unknown function '%s'", nameToken.Value)
+	}
+
+	if err := parser.checkFunction(nameToken, funcType); err != nil {
+		return nil, err
+	}
+
+	parser.next()
+
+	var filter DQLizer
+
+	switch funcType {
+	case typeFunc:
+		typeToken, err := parser.expect(tokenName, "a type name")
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter = Type(typeToken.Value)
+	case hasFunc:
+		predicate, err := parser.parseAllowedPredicate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter = Has(predicate)
+	case regexpFunc:
+		predicate, err := parser.parseAllowedPredicate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := parser.expect(tokenComma, "','"); err != nil {
+			return nil, err
+		}
+
+		patternToken, err := parser.expect(tokenRegex, "a regular expression")
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter, err = parser.newRegexp(patternToken, predicate, patternToken.Value)
+
+		if err != nil {
+			return nil, err
+		}
+	case betweenFunc:
+		predicate, err := parser.parseAllowedPredicate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := parser.parseArgumentValues(2)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter = Between(predicate, values[0], values[1])
+	default:
+		predicate, err := parser.parseAllowedPredicate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := parser.parseArgumentValues(1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filter, _ = newFilterKV(funcType, filterKV{predicate: values[0]})
+	}
+
+	if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (parser *filterParser) parseArgumentValues(count int) ([]interface{}, error) {
+	values := make([]interface{}, count)
+
+	for index := range values {
+		if _, err := parser.expect(tokenComma, "','"); err != nil {
+			return nil, err
+		}
+
+		value, err := parser.parseLiteral()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[index] = value
+	}
+
+	return values, nil
+}
+
+func (parser *filterParser) parseAllowedPredicate() (string, error) {
+	predicateToken := parser.peek()
+
+	if predicateToken.Type != tokenName && predicateToken.Type != tokenIRI {
+		return "", parser.unexpected(predicateToken, "a predicate")
+	}
+
+	predicate, err := parser.parsePredicateName()
+
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.SplitN(predicate, "@", 2)[0]
+
+	if parser.language.predicates != nil && !parser.language.predicates[name] {
+		return "", parser.errorf(predicateToken, "predicate '%s' is not allowed", name)
+	}
+
+	return predicate, nil
+}
+
+func (parser *filterParser) checkFunction(tok token, funcType FuncType) error {
+	if parser.language.functions != nil && !parser.language.functions[string(funcType)] {
+		return parser.errorf(tok, "function '%s' is not allowed", funcType)
+	}
+	return nil
+}
+
+// parseLiteral parses a value, variables and expressions are not accepted
+func (parser *filterParser) parseLiteral() (interface{}, error) {
+	tok := parser.peek()
+
+	switch {
+	case tok.Type == tokenOperator && tok.Value == "-" && parser.peekAt(1).Type == tokenNumber:
+		parser.next()
+		return parseNumber("-" + parser.next().Value), nil
+	case tok.Type == tokenString, tok.Type == tokenNumber, tok.Type == tokenLeftSquare,
+		isKeyword(tok, "true"), isKeyword(tok, "false"):
+	default:
+		return nil, parser.unexpected(tok, "a value")
+	}
+
+	if tok.Type != tokenLeftSquare {
+		return parser.parseValue()
+	}
+
+	parser.next()
+	values := []interface{}{}
+
+	for !parser.is(tokenRightSquare) {
+		value, err := parser.parseLiteral()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+
+		if !parser.is(tokenRightSquare) {
+			if _, err := parser.expect(tokenComma, "',' or ']'"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	parser.next()
+	return values, nil
+}
+
+// newRegexp validates the pattern, the filter sends it as a GraphQL variable
+func (parser *filterParser) newRegexp(tok token, predicate string, pattern string) (DQLizer, error) {
+	expression, err := NewRegexp(predicate, pattern)
+
+	if err != nil {
+		return nil, parser.errorf(tok, "%s", err.Error())
+	}
+
+	return expression, nil
+}
+
+// globToRegexp converts a glob pattern into an anchored regular expression
+//
+// Example: ali* -> /^ali.*$/
+func globToRegexp(pattern string) string {
+	writer := strings.Builder{}
+	writer.WriteString("/^")
+
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			writer.WriteString(".*")
+		case '?':
+			writer.WriteString(".")
+		case '/':
+			writer.WriteString(`\/`)
+		default:
+			writer.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	writer.WriteString("$/")
+	return writer.String()
+}
@@ -0,0 +1,125 @@
+package dqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func mustRegexp(predicate string, pattern string) dql.SafeRegexp {
+	expression, err := dql.NewRegexp(predicate, pattern)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return expression
+}
+
+func Test_Parse_Filter(t *testing.T) {
+	filter, err := dql.ParseFilter(`age > 5 AND (name ~ "ali*" OR has(email))`)
+	require.NoError(t, err)
+
+	require.Equal(t, dql.And{
+		dql.Gt{"age": 5},
+		dql.Or{
+			mustRegexp("name", "/^ali.*$/"),
+			dql.Has("email"),
+		},
+	}, filter)
+
+	query, variables, err := dql.Query(dql.TypeFn("User")).Filter(filter).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"$0": "5", "$1": "/^ali.*$/"}, variables)
+
+	expected := dql.Minify(`
+		query Rootquery($0:int, $1:string) {
+			<rootQuery>(func: type(<User>)) @filter((gt(<age>,$0) AND (regexp(<name>,$1) OR has(<email>)))) {  }
+		}
+	`)
+
+	require.Equal(t, expected, dql.Minify(query))
+}
+
+func Test_Parse_Filter_Operators(t *testing.T) {
+	cases := map[string]dql.DQLizer{
+		`age >= 18`:                             dql.Ge{"age": 18},
+		`age < -1.5`:                            dql.Lt{"age": -1.5},
+		`age <= 65`:                             dql.Le{"age": 65},
+		`name = "alice"`:                        dql.Eq{"name": "alice"},
+		`name@en != "alice"`:                    dql.Not{dql.Eq{"name@en": "alice"}},
+		`active = true`:                         dql.Eq{"active": true},
+		`status in ["a", "b"]`:                  dql.Eq{"status": []interface{}{"a", "b"}},
+		`not has(email)`:                        dql.Not{dql.Has("email")},
+		`type(User)`:                            dql.Type("User"),
+		`anyofterms(name, "alice bob")`:         dql.AnyOfTerms{"name": "alice bob"},
+		`regexp(name, /^a.*b$/i)`:               mustRegexp("name", "/^a.*b$/i"),
+		`between(age, 18, 30)`:                  dql.Between("age", 18, 30),
+		`name~"a?c/d.e"`:                        mustRegexp("name", `/^a.c\/d\.e$/`),
+		`a = 1 or b = 2 and c = 3`:              dql.Or{dql.Eq{"a": 1}, dql.And{dql.Eq{"b": 2}, dql.Eq{"c": 3}}},
+		`(a = 1 OR b = 2) AND NOT (c = 3)`:      dql.And{dql.Or{dql.Eq{"a": 1}, dql.Eq{"b": 2}}, dql.Not{dql.Eq{"c": 3}}},
+		`a = 1 AND b = 2 AND gt(c, 3)`:          dql.And{dql.Eq{"a": 1}, dql.Eq{"b": 2}, dql.Gt{"c": 3}},
+		`alloftext(bio, "go developer") OR x=1`: dql.Or{dql.AllOfText{"bio": "go developer"}, dql.Eq{"x": 1}},
+	}
+
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			filter, err := dql.ParseFilter(input)
+			require.NoError(t, err)
+			require.Equal(t, expected, filter)
+		})
+	}
+}
+
+func Test_Parse_Filter_Allowlist(t *testing.T) {
+	language := dql.NewFilterLanguage(
+		dql.WithAllowedPredicates("age", "name"),
+		dql.WithAllowedFunctions("eq", "gt", "has"),
+	)
+
+	_, err := language.Parse(`age > 5 AND name@en = "alice" AND has(name)`)
+	require.NoError(t, err)
+
+	_, err = language.Parse(`age > 5 AND password = "secret"`)
+	require.EqualError(t, err, "line 1, column 13: predicate 'password' is not allowed")
+
+	_, err = language.Parse(`age > 5 OR name ~ "a*"`)
+	require.EqualError(t, err, "line 1, column 17: function 'regexp' is not allowed")
+
+	_, err = language.Parse(`anyofterms(name, "a")`)
+	require.EqualError(t, err, "line 1, column 1: function 'anyofterms' is not allowed")
+}
+
+func Test_Parse_Filter_Errors(t *testing.T) {
+	cases := map[string]string{
+		``:                         "line 1, column 1: empty filter",
+		`age >`:                    "line 1, column 6: expected a value, found end of input",
+		`age 5`:                    "line 1, column 5: expected a comparison operator, found '5'",
+		`age > 5 AND`:              "line 1, column 12: expected a predicate, found end of input",
+		`(age > 5`:                 "line 1, column 9: expected ')', found end of input",
+		`age > 5 name = "x"`:       "line 1, column 9: expected AND, OR or end of input, found 'name'",
+		`uid(0x1)`:                 "line 1, column 1: unknown function 'uid'",
+		`name = $name`:             "line 1, column 8: expected a value, found '$name'",
+		`name ~ 5`:                 "line 1, column 8: the ~ operator expects a string pattern",
+		`regexp(name, "a")`:        "line 1, column 14: expected a regular expression, found 'a'",
+		`regexp(name, /a(/i)`:      "line 1, column 14: regexp on 'name': error parsing regexp: missing closing ): `a(`",
+		`regexp(name, /a/iiiiq)`:   "line 1, column 14: regexp on 'name': unsupported flags 'iiiiq'",
+		"name = \"a\nb":            "line 1, column 8: unterminated string",
+		`status in "a"`:            "line 1, column 11: expected a list, found 'a'",
+		`between(age, 1)`:          "line 1, column 15: expected ',', found ')'",
+		`name = has(email)`:        "line 1, column 8: expected a value, found 'has'",
+		`eq(name, "a") AND name =`: "line 1, column 25: expected a value, found end of input",
+	}
+
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			_, err := dql.ParseFilter(input)
+
+			var parseError *dql.ParseError
+			require.True(t, errors.As(err, &parseError))
+			require.EqualError(t, err, expected)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package dqlx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// symbolFingerprintValue replaces the values in the template of a fingerprint
+const symbolFingerprintValue = "?"
+
+// QueryFingerprint identifies the shape of a query regardless of its values
+type QueryFingerprint struct {
+	// Hash the sha256 hex digest of the template
+	Hash string
+	// Template the query on a single line, values replaced with ?
+	Template string
+}
+
+// Fingerprint returns the fingerprint of a DQL document. Variables, literal values
+// and whitespace are not significant: queries differing only by their values
+// or by the number of values of a list share the same fingerprint.
+// The fingerprint is meant for metrics labels, slow queries aggregation and cache keys
+//
+// Example:
+//   query, _, _ := dqlx.Query(...).ToDQL()
+//   fingerprint, err := dqlx.Fingerprint(query)
+//
+//   queryDuration.WithLabelValues(fingerprint.Hash).Observe(elapsed)
+func Fingerprint(query string) (QueryFingerprint, error) {
+	template, err := queryTemplate(query)
+
+	if err != nil {
+		return QueryFingerprint{}, err
+	}
+
+	return QueryFingerprint{
+		Hash:     hashTemplate(template),
+		Template: template,
+	}, nil
+}
+
+// queryTemplate returns the body of the document on a single line,
+// values replaced with ? and lists collapsed to a single value
+func queryTemplate(query string) (string, error) {
+	tokens, err := tokenize(query, false)
+
+	if err != nil {
+		return "", err
+	}
+
+	_, tokens, err = splitQueryHeader(tokens)
+
+	if err != nil {
+		return "", err
+	}
+
+	template := make([]token, 0, len(tokens))
+	inList := false
+
+	for index, tok := range tokens {
+		switch tok.Type {
+		case tokenLeftSquare:
+			inList = true
+		case tokenRightSquare:
+			inList = false
+		}
+
+		if isFingerprintValue(tokens, index) {
+			tok = token{Type: tokenName, Value: symbolFingerprintValue, Pos: tok.Pos}
+		}
+
+		// lists of values are collapsed to a single value
+		if inList && tok.Value == symbolFingerprintValue && len(template) > 1 &&
+			template[len(template)-1].Type == tokenComma &&
+			template[len(template)-2].Value == symbolFingerprintValue {
+			template = template[:len(template)-1]
+			continue
+		}
+
+		template = append(template, tok)
+	}
+
+	return Minify(NewPrettyPrinter().formatTokens(template)), nil
+}
+
+func hashTemplate(template string) string {
+	sum := sha256.Sum256([]byte(template))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint returns the fingerprint of the query
+//
+// Example:
+//   fingerprint, err := dqlx.Query(...).Fingerprint()
+func (builder QueryBuilder) Fingerprint() (QueryFingerprint, error) {
+	query, _, err := builder.ToDQL()
+
+	if err != nil {
+		return QueryFingerprint{}, err
+	}
+
+	return Fingerprint(query)
+}
+
+// isFingerprintValue determines if the token is a value
+func isFingerprintValue(tokens []token, index int) bool {
+	switch tokens[index].Type {
+	case tokenVariable, tokenString, tokenNumber, tokenRegex:
+		return true
+	case tokenName:
+		value := strings.ToLower(tokens[index].Value)
+		return (value == "true" || value == "false") && index > 0 && tokens[index-1].Type == tokenComma
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	query := func(name string, tags []string, first int) dql.QueryBuilder {
+		return dql.Query(dql.EqFn("name", name)).
+			Filter(dql.Eq{"tags": tags}, dql.Between("age", 18, 30)).
+			Paginate(dql.Cursor{First: first}).
+			Select(`
+				uid
+				name
+			`)
+	}
+
+	fingerprint, err := query("Steven", []string{"a"}, 10).Fingerprint()
+	require.NoError(t, err)
+
+	require.Equal(t, "{ <rootQuery>(func: eq(<name>, ?), first: ?) @filter(eq(<tags>, [?]) AND between(<age>, ?, ?)) { <uid> <name> } }", fingerprint.Template)
+	require.Len(t, fingerprint.Hash, 64)
+
+	dqlQuery, _, err := query("Steven", []string{"a"}, 10).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.QueryHash(dqlQuery), fingerprint.Hash)
+
+	other, err := query(`Say "hi"`, []string{"a", "b", "c"}, 20).Fingerprint()
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, other)
+
+	different, err := query("Steven", []string{"a"}, 10).OrderAsc("name").Fingerprint()
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint.Hash, different.Hash)
+}
+
+func TestFingerprintDQL(t *testing.T) {
+	fingerprint, err := dql.Fingerprint(`
+		query Q($0: string) {
+			q(func: uid(0x1, 0x2)) @filter(eq(active, true) AND regexp(name, /^Ste/i) AND eq(name, $0)) {
+				uid
+			}
+		}
+	`)
+	require.NoError(t, err)
+
+	other, err := dql.Fingerprint(`{ q(func: uid(0x3, 0x4)) @filter(eq(active, false) AND regexp(name, /^a/) AND eq(name, "Steven")) { uid } }`)
+	require.NoError(t, err)
+
+	require.Equal(t, fingerprint, other)
+	require.Equal(t, "{ q(func: uid(?, ?)) @filter(eq(active, ?) AND regexp(name, ?) AND eq(name, ?)) { uid } }", fingerprint.Template)
+
+	_, err = dql.Fingerprint(`{ q(func: eq(name, "unterminated)) { uid } }`)
+	require.Error(t, err)
+}
+
+func TestFingerprintIncompleteDQL(t *testing.T) {
+	_, err := dql.Fingerprint(`query`)
+	require.EqualError(t, err, "line 1, column 6: expected '{' after the query header")
+
+	_, err = dql.Fingerprint(`query Q($0: string)`)
+	require.EqualError(t, err, "line 1, column 20: expected '{' after the query header")
+
+	fingerprint, err := dql.Fingerprint(`query Q($0: string) { q(func: eq(name, $0)) { uid`)
+	require.NoError(t, err)
+	require.Equal(t, "{ q(func: eq(name, ?)) { uid } }", fingerprint.Template)
+}
@@ -1,9 +1,398 @@
 package dqlx
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // Minify minifies a dql query
 func Minify(query string) string {
 	parts := strings.Fields(query)
 	return strings.Join(parts, " ")
 }
+
+// PrettyPrinter formats DQL documents in a readable way
+type PrettyPrinter struct {
+	indent           string
+	compactSelection bool
+	sortDirectives   bool
+}
+
+// PrettyOptionFn used to modify options of the pretty printer
+type PrettyOptionFn func(printer *PrettyPrinter)
+
+// WithIndent configures the indentation used for each nesting level
+func WithIndent(indent string) PrettyOptionFn {
+	return func(printer *PrettyPrinter) {
+		printer.indent = indent
+	}
+}
+
+// WithCompactSelection prints consecutive predicates
+// without a nested selection on the same line
+func WithCompactSelection(compact bool) PrettyOptionFn {
+	return func(printer *PrettyPrinter) {
+		printer.compactSelection = compact
+	}
+}
+
+// WithSortedDirectives prints the directives of a block in a stable order:
+// @filter, @facets, @groupby, @cascade, @normalize, @recurse, @ignorereflex
+func WithSortedDirectives(sorted bool) PrettyOptionFn {
+	return func(printer *PrettyPrinter) {
+		printer.sortDirectives = sorted
+	}
+}
+
+// NewPrettyPrinter creates a new PrettyPrinter,
+// by default it indents with 2 spaces and sorts the directives
+func NewPrettyPrinter(options ...PrettyOptionFn) PrettyPrinter {
+	printer := PrettyPrinter{
+		indent:         "  ",
+		sortDirectives: true,
+	}
+
+	for _, option := range options {
+		option(&printer)
+	}
+
+	return printer
+}
+
+// Pretty formats a DQL document with one predicate per line
+//
+// Example:
+//   query, _, _ := dqlx.Query(...).ToDQL()
+//   pretty, err := dqlx.Pretty(query, dqlx.WithIndent("\t"))
+func Pretty(query string, options ...PrettyOptionFn) (string, error) {
+	return NewPrettyPrinter(options...).Format(query)
+}
+
+// ToPrettyDQL returns the formatted DQL statement of the query
+//
+// Example:
+//   query, variables, err := dqlx.Query(...).ToPrettyDQL()
+func (builder QueryBuilder) ToPrettyDQL(options ...PrettyOptionFn) (query string, args map[string]string, err error) {
+	query, args, err = builder.ToDQL()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	query, err = Pretty(query, options...)
+	return query, args, err
+}
+
+// Format formats a DQL document, comments are preserved
+func (printer PrettyPrinter) Format(query string) (string, error) {
+	tokens, err := tokenize(query, true)
+
+	if err != nil {
+		return "", err
+	}
+
+	return printer.formatTokens(tokens), nil
+}
+
+func (printer PrettyPrinter) formatTokens(tokens []token) string {
+	writer := &prettyWriter{
+		printer: printer,
+		tokens:  tokens,
+	}
+
+	writer.formatSelectionSet(0)
+
+	return strings.TrimRight(writer.output.String(), "\n")
+}
+
+var directiveOrder = map[string]int{
+	"filter":       0,
+	"facets":       1,
+	"groupby":      2,
+	"cascade":      3,
+	"normalize":    4,
+	"recurse":      5,
+	"ignorereflex": 6,
+}
+
+type prettyWriter struct {
+	printer PrettyPrinter
+	tokens  []token
+	current int
+	output  strings.Builder
+}
+
+// peek returns the current token, or EOF after the last token
+func (writer *prettyWriter) peek() token {
+	if writer.current >= len(writer.tokens) {
+		return token{Type: tokenEOF}
+	}
+
+	return writer.tokens[writer.current]
+}
+
+func (writer *prettyWriter) next() token {
+	tok := writer.peek()
+
+	if tok.Type != tokenEOF {
+		writer.current++
+	}
+	return tok
+}
+
+func (writer *prettyWriter) writeLine(depth int, line string) {
+	writer.output.WriteString(strings.Repeat(writer.printer.indent, depth))
+	writer.output.WriteString(line)
+	writer.output.WriteString("\n")
+}
+
+// formatSelectionSet formats the items of a selection set
+// until its closing brace, or until the end of the document for depth 0
+func (writer *prettyWriter) formatSelectionSet(depth int) {
+	var scalars []string
+
+	flushScalars := func() {
+		if len(scalars) > 0 {
+			writer.writeLine(depth, strings.Join(scalars, " "))
+		}
+		scalars = nil
+	}
+
+	for {
+		tok := writer.peek()
+
+		switch tok.Type {
+		case tokenEOF:
+			flushScalars()
+			return
+		case tokenRightCurl:
+			writer.next()
+			flushScalars()
+
+			if depth > 0 {
+				return
+			}
+
+			// unbalanced closing brace at the top level
+			writer.writeLine(depth, "}")
+			continue
+		case tokenComment:
+			writer.next()
+			flushScalars()
+			writer.writeLine(depth, tok.Value)
+			continue
+		case tokenLeftCurl:
+			writer.next()
+			flushScalars()
+			writer.writeLine(depth, "{")
+			writer.formatSelectionSet(depth + 1)
+			writer.writeLine(depth, "}")
+			continue
+		}
+
+		line := writer.formatItem(writer.readItem())
+
+		if writer.peek().Type == tokenLeftCurl {
+			writer.next()
+			flushScalars()
+			writer.writeLine(depth, line+" {")
+			writer.formatSelectionSet(depth + 1)
+			writer.writeLine(depth, "}")
+			continue
+		}
+
+		scalars = append(scalars, line)
+
+		if !writer.printer.compactSelection {
+			flushScalars()
+		}
+	}
+}
+
+// readItem reads the tokens of a single selection, block or header
+func (writer *prettyWriter) readItem() []token {
+	var item []token
+	nesting := 0
+
+	for {
+		tok := writer.peek()
+
+		if tok.Type == tokenEOF || tok.Type == tokenComment {
+			return item
+		}
+
+		if nesting == 0 {
+			if tok.Type == tokenLeftCurl || tok.Type == tokenRightCurl {
+				return item
+			}
+
+			if len(item) > 0 && startsSelection(item, tok) {
+				return item
+			}
+		}
+
+		switch tok.Type {
+		case tokenLeftParen, tokenLeftSquare:
+			nesting++
+		case tokenRightParen, tokenRightSquare:
+			nesting--
+		}
+
+		item = append(item, writer.next())
+	}
+}
+
+// startsSelection determines if the current token starts a new selection
+// given the tokens of the selection being read
+func startsSelection(item []token, current token) bool {
+	previous := item[len(item)-1]
+
+	if current.Type != tokenName && current.Type != tokenIRI {
+		return false
+	}
+
+	if isKeyword(current, "as") {
+		return false
+	}
+
+	switch previous.Type {
+	case tokenColon, tokenAt, tokenComma, tokenOperator:
+		return false
+	case tokenName:
+		return !isKeyword(previous, "as") && !(len(item) == 1 && isKeyword(previous, "query"))
+	}
+
+	return true
+}
+
+func isKeyword(tok token, keyword string) bool {
+	return tok.Type == tokenName && strings.EqualFold(tok.Value, keyword)
+}
+
+func isDirective(tokens []token, index int) bool {
+	return tokens[index].Type == tokenAt &&
+		index+1 < len(tokens) &&
+		tokens[index+1].Type == tokenName &&
+		dqlDirectives[strings.ToLower(tokens[index+1].Value)]
+}
+
+func (writer *prettyWriter) formatItem(item []token) string {
+	if writer.printer.sortDirectives {
+		item = sortDirectives(item)
+	}
+
+	line := strings.Builder{}
+
+	for index, tok := range item {
+		if index > 0 && needsSpace(item, index) {
+			line.WriteString(" ")
+		}
+		line.WriteString(tokenText(tok))
+	}
+
+	return line.String()
+}
+
+// sortDirectives moves the directives of an item in a stable order
+// at the position of the first directive
+func sortDirectives(item []token) []token {
+	var directives [][]token
+	var rest []token
+	insertAt := -1
+
+	for index := 0; index < len(item); {
+		if !isDirective(item, index) {
+			rest = append(rest, item[index])
+			index++
+			continue
+		}
+
+		end := index + 2
+
+		if end < len(item) && item[end].Type == tokenLeftParen {
+			nesting := 0
+
+			for ; end < len(item); end++ {
+				if item[end].Type == tokenLeftParen {
+					nesting++
+				} else if item[end].Type == tokenRightParen {
+					nesting--
+				}
+
+				if nesting == 0 {
+					end++
+					break
+				}
+			}
+		}
+
+		if insertAt == -1 {
+			insertAt = len(rest)
+		}
+
+		directives = append(directives, item[index:end])
+		index = end
+	}
+
+	if len(directives) < 2 {
+		return item
+	}
+
+	sort.SliceStable(directives, func(i, j int) bool {
+		return directiveRank(directives[i]) < directiveRank(directives[j])
+	})
+
+	sorted := append([]token{}, rest[:insertAt]...)
+
+	for _, directive := range directives {
+		sorted = append(sorted, directive...)
+	}
+
+	return append(sorted, rest[insertAt:]...)
+}
+
+func directiveRank(directive []token) int {
+	if rank, ok := directiveOrder[strings.ToLower(directive[1].Value)]; ok {
+		return rank
+	}
+	return len(directiveOrder)
+}
+
+func needsSpace(item []token, index int) bool {
+	previous := item[index-1]
+	current := item[index]
+
+	switch current.Type {
+	case tokenRightParen, tokenRightSquare, tokenComma, tokenColon:
+		return false
+	case tokenAt:
+		return isDirective(item, index)
+	case tokenLeftParen:
+		if previous.Type == tokenIRI {
+			return false
+		}
+
+		if previous.Type == tokenName {
+			return isKeyword(previous, "and") || isKeyword(previous, "or") || isKeyword(previous, "not")
+		}
+	}
+
+	switch previous.Type {
+	case tokenLeftParen, tokenLeftSquare, tokenAt:
+		return false
+	}
+
+	return true
+}
+
+func tokenText(tok token) string {
+	switch tok.Type {
+	case tokenIRI:
+		return "<" + tok.Value + ">"
+	case tokenString:
+		replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+		return `"` + replacer.Replace(tok.Value) + `"`
+	}
+
+	return tok.Value
+}
@@ -0,0 +1,23 @@
+package dqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyTokensWithoutEOF(t *testing.T) {
+	tokens, err := tokenize(`{ q(func: has(name)) { uid`, false)
+	require.NoError(t, err)
+
+	// drop the trailing EOF token
+	tokens = tokens[:len(tokens)-1]
+
+	require.NotPanics(t, func() {
+		require.Equal(t, "{\n  q(func: has(name)) {\n    uid\n  }\n}", NewPrettyPrinter().formatTokens(tokens))
+	})
+
+	require.NotPanics(t, func() {
+		require.Equal(t, "", NewPrettyPrinter().formatTokens(nil))
+	})
+}
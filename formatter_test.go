@@ -0,0 +1,93 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Pretty_Query_Builder(t *testing.T) {
+	query, variables, err := dql.
+		QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+		Fields(`
+			uid
+			title:name@en
+		`, dql.As("A", dql.Count("actors"))).
+		Filter(dql.Or{
+			dql.Eq{"name": "actor1"},
+			dql.Eq{"name": "actor2"},
+		}).
+		Cascade().
+		Edge("authors", dql.Fields(`
+			uid
+			name
+		`), dql.Facets("since"), dql.Gt{"age": 20}).
+		ToPrettyDQL()
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"$0": "Blade Runner",
+		"$1": "actor1",
+		"$2": "actor2",
+		"$3": "20",
+	}, variables)
+
+	expected := `query Bladerunner($0: string, $1: string, $2: string, $3: int) {
+  <bladerunner>(func: eq(<name>@en, $0)) @filter((eq(<name>, $1) OR eq(<name>, $2))) @cascade {
+    <uid>
+    <title>: <name>@en
+    A as count(<actors>)
+    <authors> @filter(gt(<age>, $3)) @facets(<since>) {
+      <uid>
+      <name>
+    }
+  }
+}`
+
+	require.Equal(t, expected, query)
+}
+
+func Test_Pretty_Options(t *testing.T) {
+	query := `# find a user
+	{ me(func: uid(0x1)) @cascade @filter(has(email)) { name@en email friends (first: 10) { uid } } }`
+
+	pretty, err := dql.Pretty(query, dql.WithIndent("\t"), dql.WithCompactSelection(true))
+	require.NoError(t, err)
+
+	expected := "# find a user\n" +
+		"{\n" +
+		"\tme(func: uid(0x1)) @filter(has(email)) @cascade {\n" +
+		"\t\tname@en email\n" +
+		"\t\tfriends(first: 10) {\n" +
+		"\t\t\tuid\n" +
+		"\t\t}\n" +
+		"\t}\n" +
+		"}"
+
+	require.Equal(t, expected, pretty)
+
+	pretty, err = dql.Pretty(query, dql.WithSortedDirectives(false))
+	require.NoError(t, err)
+	require.Contains(t, pretty, "me(func: uid(0x1)) @cascade @filter(has(email)) {")
+}
+
+func Test_Pretty_Is_Stable(t *testing.T) {
+	query, _, err := dql.
+		QueryEdge("bladerunner", dql.TypeFn("Film")).
+		Fields(`
+			uid
+			name
+		`).
+		Edge("actors", dql.Fields(`uid`), dql.OrderAsc("name"), dql.Cursor{First: 5}).
+		ToDQL()
+	require.NoError(t, err)
+
+	pretty, err := dql.Pretty(query)
+	require.NoError(t, err)
+
+	again, err := dql.Pretty(pretty)
+	require.NoError(t, err)
+
+	require.Equal(t, pretty, again)
+}
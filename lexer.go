@@ -0,0 +1,320 @@
+package dqlx
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenName
+	tokenIRI
+	tokenVariable
+	tokenString
+	tokenNumber
+	tokenRegex
+	tokenComment
+	tokenLeftCurl
+	tokenRightCurl
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftSquare
+	tokenRightSquare
+	tokenComma
+	tokenColon
+	tokenAt
+	tokenOperator
+)
+
+// Position represents a position within a DQL document
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// String returns the position in a line:column format
+func (position Position) String() string {
+	return fmt.Sprintf("%d:%d", position.Line, position.Column)
+}
+
+type token struct {
+	Type  tokenType
+	Value string
+	Pos   Position
+}
+
+// ParseError represents an error encountered while reading a DQL document
+type ParseError struct {
+	Pos     Position
+	Message string
+}
+
+// Error returns the error message together with its position
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", err.Pos.Line, err.Pos.Column, err.Message)
+}
+
+type lexer struct {
+	input  string
+	offset int
+	line   int
+	column int
+	tokens []token
+}
+
+// tokenize splits a DQL document into tokens.
+// comments are returned only if keepComments is true
+func tokenize(input string, keepComments bool) ([]token, error) {
+	lex := &lexer{
+		input:  input,
+		line:   1,
+		column: 1,
+	}
+
+	for {
+		lex.skipSpaces()
+
+		if lex.offset >= len(lex.input) {
+			lex.tokens = append(lex.tokens, token{Type: tokenEOF, Pos: lex.position()})
+			return lex.tokens, nil
+		}
+
+		tok, err := lex.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if tok.Type == tokenComment && !keepComments {
+			continue
+		}
+
+		lex.tokens = append(lex.tokens, tok)
+	}
+}
+
+func (lex *lexer) position() Position {
+	return Position{
+		Offset: lex.offset,
+		Line:   lex.line,
+		Column: lex.column,
+	}
+}
+
+func (lex *lexer) peek() rune {
+	if lex.offset >= len(lex.input) {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(lex.input[lex.offset:])
+	return r
+}
+
+func (lex *lexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(lex.input[lex.offset:])
+	lex.offset += size
+
+	if r == '\n' {
+		lex.line++
+		lex.column = 1
+	} else {
+		lex.column++
+	}
+
+	return r
+}
+
+func (lex *lexer) skipSpaces() {
+	for lex.offset < len(lex.input) && unicode.IsSpace(lex.peek()) {
+		lex.advance()
+	}
+}
+
+func (lex *lexer) errorf(pos Position, format string, args ...interface{}) error {
+	return &ParseError{
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (lex *lexer) next() (token, error) {
+	pos := lex.position()
+	r := lex.peek()
+
+	single := map[rune]tokenType{
+		'{': tokenLeftCurl,
+		'}': tokenRightCurl,
+		'(': tokenLeftParen,
+		')': tokenRightParen,
+		'[': tokenLeftSquare,
+		']': tokenRightSquare,
+		',': tokenComma,
+		':': tokenColon,
+		'@': tokenAt,
+	}
+
+	if tokenType, ok := single[r]; ok {
+		lex.advance()
+		return token{Type: tokenType, Value: string(r), Pos: pos}, nil
+	}
+
+	switch {
+	case r == '#':
+		return lex.readComment(pos), nil
+	case r == '"':
+		return lex.readString(pos)
+	case r == '<':
+		if lex.isIRI() {
+			return lex.readIRI(pos)
+		}
+		return lex.readOperator(pos), nil
+	case r == '$':
+		lex.advance()
+		name := lex.readWhile(isNameRune)
+		if name == "" {
+			return token{}, lex.errorf(pos, "expected a variable name after '$'")
+		}
+		return token{Type: tokenVariable, Value: "$" + name, Pos: pos}, nil
+	case r == '/' && lex.expectsValue():
+		return lex.readRegex(pos)
+	case r == '-' && lex.expectsValue():
+		lex.advance()
+		number := lex.readWhile(isNameRune)
+		if number == "" {
+			return token{Type: tokenOperator, Value: "-", Pos: pos}, nil
+		}
+		return token{Type: tokenNumber, Value: "-" + number, Pos: pos}, nil
+	case unicode.IsDigit(r):
+		return token{Type: tokenNumber, Value: lex.readWhile(isNameRune), Pos: pos}, nil
+	case isNameRune(r):
+		return token{Type: tokenName, Value: lex.readWhile(isNameRune), Pos: pos}, nil
+	case strings.ContainsRune("+-*/%=!><&|", r):
+		return lex.readOperator(pos), nil
+	}
+
+	return token{}, lex.errorf(pos, "unexpected character %q", r)
+}
+
+// expectsValue determines if the next token is expected to be a value,
+// used to tell apart regular expressions and negative numbers from operators
+func (lex *lexer) expectsValue() bool {
+	if len(lex.tokens) == 0 {
+		return true
+	}
+
+	previous := lex.tokens[len(lex.tokens)-1].Type
+	return previous == tokenComma || previous == tokenLeftParen || previous == tokenLeftSquare || previous == tokenColon
+}
+
+func (lex *lexer) readWhile(accept func(r rune) bool) string {
+	start := lex.offset
+
+	for lex.offset < len(lex.input) && accept(lex.peek()) {
+		lex.advance()
+	}
+
+	return lex.input[start:lex.offset]
+}
+
+func (lex *lexer) readComment(pos Position) token {
+	value := lex.readWhile(func(r rune) bool {
+		return r != '\n'
+	})
+	return token{Type: tokenComment, Value: value, Pos: pos}
+}
+
+func (lex *lexer) readString(pos Position) (token, error) {
+	lex.advance()
+	writer := strings.Builder{}
+
+	for {
+		if lex.offset >= len(lex.input) {
+			return token{}, lex.errorf(pos, "unterminated string")
+		}
+
+		r := lex.advance()
+
+		switch r {
+		case '"':
+			return token{Type: tokenString, Value: writer.String(), Pos: pos}, nil
+		case '\\':
+			if lex.offset >= len(lex.input) {
+				return token{}, lex.errorf(pos, "unterminated string")
+			}
+
+			escaped := lex.advance()
+			switch escaped {
+			case 'n':
+				writer.WriteRune('\n')
+			case 't':
+				writer.WriteRune('\t')
+			case 'r':
+				writer.WriteRune('\r')
+			default:
+				writer.WriteRune(escaped)
+			}
+		default:
+			writer.WriteRune(r)
+		}
+	}
+}
+
+// isIRI determines if the current '<' opens a predicate such as <name>
+// rather than being a comparison operator
+func (lex *lexer) isIRI() bool {
+	closing := strings.IndexAny(lex.input[lex.offset+1:], "> \t\n")
+	return closing > 0 && lex.input[lex.offset+1+closing] == '>'
+}
+
+func (lex *lexer) readIRI(pos Position) (token, error) {
+	lex.advance()
+	value := lex.readWhile(func(r rune) bool {
+		return r != '>'
+	})
+	lex.advance()
+
+	return token{Type: tokenIRI, Value: value, Pos: pos}, nil
+}
+
+func (lex *lexer) readRegex(pos Position) (token, error) {
+	start := lex.offset
+	lex.advance()
+
+	for {
+		if lex.offset >= len(lex.input) || lex.peek() == '\n' {
+			return token{}, lex.errorf(pos, "unterminated regular expression")
+		}
+
+		r := lex.advance()
+
+		if r == '\\' {
+			if lex.offset < len(lex.input) {
+				lex.advance()
+			}
+			continue
+		}
+
+		if r == '/' {
+			break
+		}
+	}
+
+	lex.readWhile(unicode.IsLetter)
+
+	return token{Type: tokenRegex, Value: lex.input[start:lex.offset], Pos: pos}, nil
+}
+
+func (lex *lexer) readOperator(pos Position) token {
+	value := lex.readWhile(func(r rune) bool {
+		return strings.ContainsRune("+-*/%=!><&|", r)
+	})
+	return token{Type: tokenOperator, Value: value, Pos: pos}
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '~'
+}
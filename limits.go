@@ -0,0 +1,273 @@
+package dqlx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// expressionNamePattern matches the names used by a raw selection,
+// every name is checked against the forbidden predicates
+var expressionNamePattern = regexp.MustCompile(`[A-Za-z_~][A-Za-z0-9_.~-]*`)
+
+// QueryLimits guardrails for queries built from user input,
+// zero values disable the corresponding limit.
+// Struct and schema type selections are checked as their predicates and edges.
+// Raw selections can't be inspected: every name they use is checked against
+// the forbidden predicates, and nested blocks are rejected when MaxDepth,
+// MaxFirst or RequirePagination are set.
+//
+// Example:
+//   limits := dqlx.QueryLimits{
+//     MaxDepth:            3,
+//     MaxFirst:            100,
+//     RequirePagination:   true,
+//     ForbiddenPredicates: []string{"password", "email"},
+//   }
+//
+//   err := limits.Check(query)
+//   response, err := query.Execute(ctx, dqlx.WithQueryLimits(limits))
+type QueryLimits struct {
+	// MaxDepth the maximum number of nested edges below a query block
+	MaxDepth int
+	// MaxBlocks the maximum number of query and variable blocks
+	MaxBlocks int
+	// MaxFirst the maximum number of nodes requested by a block or an edge
+	MaxFirst int
+	// ForbiddenPredicates predicates that can't be selected, traversed, filtered or sorted by.
+	// expand() is rejected since it could select them
+	ForbiddenPredicates []string
+	// RequirePagination requires query blocks and their edges to set First,
+	// variable blocks are not affected
+	RequirePagination bool
+}
+
+// LimitError is returned when a query exceeds its limits
+type LimitError struct {
+	// Path of the offending edge
+	Path    string
+	Message string
+}
+
+// Error returns the error message
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("query limit exceeded in %s: %s", err.Path, err.Message)
+}
+
+// WithQueryLimits checks the queries against the limits before sending them
+func WithQueryLimits(limits QueryLimits) OperationExecutorOptionFn {
+	return func(executor *OperationExecutor) {
+		executor.limits = &limits
+	}
+}
+
+// Check checks the queries against the limits
+func (limits QueryLimits) Check(queries ...QueryBuilder) error {
+	document := QueriesToAST(queries...)
+
+	if blocks := len(document.Blocks) + len(document.Variables); limits.MaxBlocks > 0 && blocks > limits.MaxBlocks {
+		return &LimitError{
+			Path:    "document",
+			Message: fmt.Sprintf("%d blocks requested, the maximum is %d", blocks, limits.MaxBlocks),
+		}
+	}
+
+	checker := limitsChecker{
+		limits:    limits,
+		forbidden: map[string]bool{},
+	}
+
+	for _, predicate := range limits.ForbiddenPredicates {
+		checker.forbidden[predicate] = true
+	}
+
+	for _, variable := range document.Variables {
+		if err := checker.checkEdge(variable, variable.Name, 0, false); err != nil {
+			return err
+		}
+	}
+
+	for _, block := range document.Blocks {
+		if err := checker.checkEdge(block, block.Name, 0, limits.RequirePagination); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type limitsChecker struct {
+	limits    QueryLimits
+	forbidden map[string]bool
+}
+
+func (checker limitsChecker) checkEdge(edgeNode *EdgeNode, path string, depth int, requirePagination bool) error {
+	limits := checker.limits
+
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return &LimitError{
+			Path:    path,
+			Message: fmt.Sprintf("the maximum depth is %d", limits.MaxDepth),
+		}
+	}
+
+	first := edgeNode.Pagination.First
+
+	if first < 0 {
+		first = -first
+	}
+
+	if limits.MaxFirst > 0 && first > limits.MaxFirst {
+		return &LimitError{
+			Path:    path,
+			Message: fmt.Sprintf("first is %d, the maximum is %d", first, limits.MaxFirst),
+		}
+	}
+
+	if requirePagination && first == 0 {
+		return &LimitError{
+			Path:    path,
+			Message: "pagination is required",
+		}
+	}
+
+	if depth > 0 {
+		if err := checker.checkPredicate(edgeNode.Name, path); err != nil {
+			return err
+		}
+	}
+
+	if err := checker.checkRawSelections(edgeNode, path); err != nil {
+		return err
+	}
+
+	if err := checker.checkPredicates(edgeNode, path); err != nil {
+		return err
+	}
+
+	for _, nestedEdge := range edgeNode.Edges {
+		if err := checker.checkEdge(nestedEdge, EdgePath(path, nestedEdge.Name), depth+1, requirePagination); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPredicates checks the predicates used by an edge
+// against the forbidden predicates
+func (checker limitsChecker) checkPredicates(edgeNode *EdgeNode, path string) error {
+	if len(checker.forbidden) == 0 {
+		return nil
+	}
+
+	var predicates []string
+
+	for _, selection := range edgeNode.Selections {
+		if selection.Expression != nil {
+			continue
+		}
+
+		if strings.HasPrefix(selection.Predicate, "expand(") {
+			return &LimitError{
+				Path:    path,
+				Message: "expand() can't be used with forbidden predicates",
+			}
+		}
+
+		predicates = append(predicates, selection.Predicate)
+	}
+
+	for _, order := range edgeNode.Order {
+		predicates = append(predicates, order.Predicate)
+	}
+
+	predicates = append(predicates, edgeNode.GroupBy...)
+
+	if edgeNode.Cascade != nil {
+		predicates = append(predicates, edgeNode.Cascade.Fields...)
+	}
+
+	var filters []Node
+
+	if edgeNode.RootFilter != nil {
+		filters = append(filters, edgeNode.RootFilter)
+	}
+
+	for _, filter := range edgeNode.Filters {
+		filters = append(filters, filter)
+	}
+
+	for _, filter := range filters {
+		Inspect(filter, func(node Node) bool {
+			if filterNode, ok := node.(*FilterNode); ok && !isVariableFunc(filterNode.Func) {
+				predicates = append(predicates, filterNode.Predicate)
+			}
+			return true
+		})
+	}
+
+	for _, predicate := range predicates {
+		if err := checker.checkPredicate(predicate, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRawSelections checks the selections the AST can't inspect
+func (checker limitsChecker) checkRawSelections(edgeNode *EdgeNode, path string) error {
+	limits := checker.limits
+
+	for _, selection := range edgeNode.Selections {
+		if selection.Expression == nil {
+			continue
+		}
+
+		statement, _, err := selection.Expression.ToDQL()
+
+		if err != nil {
+			return err
+		}
+
+		if strings.Contains(statement, "{") && (limits.MaxDepth > 0 || limits.MaxFirst > 0 || limits.RequirePagination) {
+			return &LimitError{
+				Path:    path,
+				Message: fmt.Sprintf("the nested blocks of '%s' can't be checked", statement),
+			}
+		}
+
+		if len(checker.forbidden) == 0 {
+			continue
+		}
+
+		if strings.Contains(statement, "expand(") {
+			return &LimitError{
+				Path:    path,
+				Message: "expand() can't be used with forbidden predicates",
+			}
+		}
+
+		for _, name := range expressionNamePattern.FindAllString(statement, -1) {
+			if err := checker.checkPredicate(name, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (checker limitsChecker) checkPredicate(predicate string, path string) error {
+	predicate = strings.SplitN(predicate, "@", 2)[0]
+
+	if checker.forbidden[predicate] {
+		return &LimitError{
+			Path:    path,
+			Message: fmt.Sprintf("the predicate '%s' is forbidden", predicate),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package dqlx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLimits(t *testing.T) {
+	query := dql.QueryType("User").
+		Paginate(dql.Cursor{First: 10}).
+		Select("name").
+		Edge("friends", dql.Select("name"), dql.Cursor{First: 50}).
+		Edge("friends->posts", dql.Select("title"), dql.Cursor{First: 5})
+
+	t.Run("within limits", func(t *testing.T) {
+		err := dql.QueryLimits{
+			MaxDepth:            2,
+			MaxBlocks:           1,
+			MaxFirst:            50,
+			RequirePagination:   true,
+			ForbiddenPredicates: []string{"password"},
+		}.Check(query)
+
+		require.NoError(t, err)
+	})
+
+	cases := map[string]struct {
+		limits   dql.QueryLimits
+		query    dql.QueryBuilder
+		expected string
+	}{
+		"depth": {
+			limits:   dql.QueryLimits{MaxDepth: 1},
+			query:    query,
+			expected: "query limit exceeded in rootQuery->friends->posts: the maximum depth is 1",
+		},
+		"blocks": {
+			limits:   dql.QueryLimits{MaxBlocks: 1},
+			query:    query.Variable(dql.Variable(dql.TypeFn("Post")).Select("P as uid")),
+			expected: "query limit exceeded in document: 2 blocks requested, the maximum is 1",
+		},
+		"first": {
+			limits:   dql.QueryLimits{MaxFirst: 20},
+			query:    query,
+			expected: "query limit exceeded in rootQuery->friends: first is 50, the maximum is 20",
+		},
+		"last": {
+			limits:   dql.QueryLimits{MaxFirst: 20},
+			query:    dql.QueryType("User").Paginate(dql.Cursor{First: -30}),
+			expected: "query limit exceeded in rootQuery: first is 30, the maximum is 20",
+		},
+		"pagination": {
+			limits:   dql.QueryLimits{RequirePagination: true},
+			query:    query.Edge("friends->posts->comments"),
+			expected: "query limit exceeded in rootQuery->friends->posts->comments: pagination is required",
+		},
+		"forbidden selection": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query:    query.Edge("friends", dql.Select("password")),
+			expected: "query limit exceeded in rootQuery->friends: the predicate 'password' is forbidden",
+		},
+		"forbidden edge": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"~owner"}},
+			query:    query.Edge("friends->~owner"),
+			expected: "query limit exceeded in rootQuery->friends->~owner: the predicate '~owner' is forbidden",
+		},
+		"forbidden filter": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"email"}},
+			query:    query.Filter(dql.Or{dql.Has("name"), dql.Not{dql.Eq{"email@en": "a@b.c"}}}),
+			expected: "query limit exceeded in rootQuery: the predicate 'email' is forbidden",
+		},
+		"forbidden order": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"salary"}},
+			query:    query.OrderDesc("salary"),
+			expected: "query limit exceeded in rootQuery: the predicate 'salary' is forbidden",
+		},
+		"forbidden count": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"followers"}},
+			query:    query.Select(dql.Count("followers")),
+			expected: "query limit exceeded in rootQuery: the predicate 'followers' is forbidden",
+		},
+		"forbidden struct field": {
+			limits: dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query: query.Select(struct {
+				Name     string `json:"name"`
+				Password string `json:"password"`
+			}{}),
+			expected: "query limit exceeded in rootQuery: the predicate 'password' is forbidden",
+		},
+		"struct depth": {
+			limits:   dql.QueryLimits{MaxDepth: 1},
+			query:    dql.QueryType("User").Select(selectedPerson{}),
+			expected: "query limit exceeded in rootQuery->films->genre: the maximum depth is 1",
+		},
+		"struct pagination": {
+			limits:   dql.QueryLimits{RequirePagination: true},
+			query:    dql.QueryType("User").Paginate(dql.Cursor{First: 10}).Select(selectedPerson{}),
+			expected: "query limit exceeded in rootQuery->films: pagination is required",
+		},
+		"forbidden type selection": {
+			limits: dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query: func() dql.QueryBuilder {
+				_, user := newSelectionSchema()
+				return query.Select(user)
+			}(),
+			expected: "query limit exceeded in rootQuery: the predicate 'password' is forbidden",
+		},
+		"type selection depth": {
+			limits: dql.QueryLimits{MaxDepth: 1},
+			query: func() dql.QueryBuilder {
+				_, user := newSelectionSchema()
+				return dql.QueryType("User").Select(dql.SelectType(user, dql.WithEdgeDepth(2)))
+			}(),
+			expected: "query limit exceeded in rootQuery->posts->author: the maximum depth is 1",
+		},
+		"forbidden raw selection": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query:    query.Select(dql.Alias("secret", dql.Expr("password"))),
+			expected: "query limit exceeded in rootQuery: the predicate 'password' is forbidden",
+		},
+		"raw nested selection": {
+			limits:   dql.QueryLimits{MaxDepth: 3},
+			query:    query.Select(dql.Expr("friends { friends { uid } }")),
+			expected: "query limit exceeded in rootQuery: the nested blocks of 'friends { friends { uid } }' can't be checked",
+		},
+		"forbidden cascade": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query:    query.Cascade("password"),
+			expected: "query limit exceeded in rootQuery: the predicate 'password' is forbidden",
+		},
+		"forbidden expand": {
+			limits:   dql.QueryLimits{ForbiddenPredicates: []string{"password"}},
+			query:    query.Select("expand(_all_)"),
+			expected: "query limit exceeded in rootQuery: expand() can't be used with forbidden predicates",
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.limits.Check(testCase.query)
+
+			var limitError *dql.LimitError
+			require.True(t, errors.As(err, &limitError))
+			require.EqualError(t, err, testCase.expected)
+		})
+	}
+
+	t.Run("variable blocks don't require pagination", func(t *testing.T) {
+		err := dql.QueryLimits{RequirePagination: true}.Check(
+			query.Variable(dql.Variable(dql.TypeFn("Post")).Select("P as uid")),
+		)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("executor", func(t *testing.T) {
+		_, err := query.Execute(context.Background(), dql.WithClient(nil), dql.WithQueryLimits(dql.QueryLimits{MaxDepth: 1}))
+		require.EqualError(t, err, "query limit exceeded in rootQuery->friends->posts: the maximum depth is 1")
+	})
+}
@@ -1,10 +1,9 @@
 package dqlx
 
 import (
-	"bytes"
 	"context"
+	"fmt"
 	"github.com/dgraph-io/dgo/v200"
-	"strings"
 )
 
 // MutationBuilder used to construct mutations
@@ -83,37 +82,105 @@ func (builder MutationBuilder) WithDClient(client *dgo.Dgraph) MutationBuilder {
 	return builder
 }
 
+// Clone returns a copy of the mutation with a deep copy of its query.
+// The data to set or delete is shared with the copy
+func (builder MutationBuilder) Clone() MutationBuilder {
+	builder.query = builder.query.Clone()
+	return builder
+}
+
 type mutationCondition struct {
 	Filters []DQLizer
 }
 
-// Condition returns a condition statement
+// Condition returns a condition statement, multiple filters are joined with AND.
+// Values are validated and inlined as literals, since conditions can't use variables
+//
+// Example:
+//   dqlx.Condition(dqlx.Len("v").Eq(0))
+//   dqlx.Condition(dqlx.Or{dqlx.Len("v").Eq(0), dqlx.Not{dqlx.Len("u").Gt(1)}})
 func Condition(filters ...DQLizer) DQLizer {
 	return mutationCondition{Filters: filters}
 }
 
 // ToDQL returns a DQL statement for a mutation condition
 func (condition mutationCondition) ToDQL() (query string, args []interface{}, err error) {
-	writer := bytes.Buffer{}
+	writer := &dqlWriter{mode: bindLiterals}
 	writer.WriteString(" @if(")
 
-	var statements []string
-	for _, filter := range condition.Filters {
-		filterDql, filterArgs, err := filter.ToDQL()
-
-		if err != nil {
-			return "", nil, err
-		}
-
-		filterDql, _ = replacePlaceholders(filterDql, filterArgs, func(index int, value interface{}) string {
-			return toVariableValue(value)
-		})
+	if err := writer.writeParts(condition.Filters, " AND "); err != nil {
+		return "", nil, err
+	}
 
-		statements = append(statements, filterDql)
+	if writer.err != nil {
+		return "", nil, writer.err
 	}
 
-	writer.WriteString(strings.Join(statements, " "))
 	writer.WriteString(") ")
 
-	return writer.String(), nil, err
+	return writer.String(), nil, nil
+}
+
+// LenCondition compares the number of nodes of a variable
+type LenCondition struct {
+	variable string
+}
+
+// Len returns a condition on the number of nodes of a variable
+//
+// Example:
+//   dqlx.Len("v").Eq(0) // eq(len(v),0)
+func Len(variable string) LenCondition {
+	return LenCondition{variable: variable}
+}
+
+// Eq represents eq(len(variable), value)
+func (condition LenCondition) Eq(value int) DQLizer {
+	return condition.compare(eqFunc, value)
+}
+
+// Gt represents gt(len(variable), value)
+func (condition LenCondition) Gt(value int) DQLizer {
+	return condition.compare(gtFunc, value)
+}
+
+// Ge represents ge(len(variable), value)
+func (condition LenCondition) Ge(value int) DQLizer {
+	return condition.compare(geFunc, value)
+}
+
+// Lt represents lt(len(variable), value)
+func (condition LenCondition) Lt(value int) DQLizer {
+	return condition.compare(ltFunc, value)
+}
+
+// Le represents le(len(variable), value)
+func (condition LenCondition) Le(value int) DQLizer {
+	return condition.compare(leFunc, value)
+}
+
+func (condition LenCondition) compare(funcType FuncType, value int) DQLizer {
+	return lenComparison{funcType: funcType, variable: condition.variable, value: value}
+}
+
+type lenComparison struct {
+	funcType FuncType
+	variable string
+	value    int
+}
+
+// ToDQL returns the DQL statement for a len comparison
+func (comparison lenComparison) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(comparison)
+}
+
+func (comparison lenComparison) renderDQL(writer *dqlWriter) error {
+	if !strictVariablePattern.MatchString(comparison.variable) {
+		return fmt.Errorf("invalid variable '%s' in len condition", comparison.variable)
+	}
+
+	return functionExpr{
+		funcType: comparison.funcType,
+		args:     []interface{}{Expr("len(" + comparison.variable + ")"), comparison.value},
+	}.renderDQL(writer)
 }
@@ -0,0 +1,64 @@
+package dqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationClone(t *testing.T) {
+	query := QueryType("User").Edge("friends", Select("name"))
+	mutation := Mutation().Query(query).Set(map[string]string{"name": "alice"})
+
+	clone := mutation.Clone()
+	clone.query = clone.query.Edge("posts")
+
+	original, _, err := mutation.query.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, "query Rootquery() { <rootQuery>(func: type(<User>)) { <friends> { <name> } } }", original)
+
+	cloned, _, err := clone.query.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, "query Rootquery() { <rootQuery>(func: type(<User>)) { <friends> { <name> } <posts> {  } } }", cloned)
+	require.Equal(t, mutation.setData, clone.setData)
+}
+
+func TestMutationCondition(t *testing.T) {
+	t.Run("len", func(t *testing.T) {
+		query, args, err := Condition(Len("v").Eq(0), Len("u").Gt(1)).ToDQL()
+		require.NoError(t, err)
+		require.Len(t, args, 0)
+		require.Equal(t, " @if(eq(len(v),0) AND gt(len(u),1)) ", query)
+	})
+
+	t.Run("connectives", func(t *testing.T) {
+		query, _, err := Condition(Or{Len("v").Le(0), Not{Len("u").Ge(2)}}).ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, " @if((le(len(v),0) OR NOT (ge(len(u),2)))) ", query)
+	})
+
+	t.Run("mutation builder", func(t *testing.T) {
+		mutation := Mutation().Condition(Len("v").Lt(1))
+
+		query, _, err := mutation.condition.ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, " @if(lt(len(v),1)) ", query)
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		query, _, err := Condition(Eq{"name": `a") OR has(password) AND eq(x, "`}, Eq{"owner": "0x1f"}, Gt{"age": 18.5}).ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, ` @if(eq(<name>,"a\") OR has(password) AND eq(x, \"") AND eq(<owner>,0x1f) AND gt(<age>,18.5)) `, query)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, _, err := Condition(Eq{"name": "a\nb"}).ToDQL()
+		require.EqualError(t, err, `control characters are not allowed in a mutation condition: "a\nb"`)
+
+		_, _, err = Condition(Eq{"name": struct{}{}}).ToDQL()
+		require.EqualError(t, err, "unsupported value of type struct {} in a mutation condition")
+
+		_, _, err = Condition(Len("v) OR has(x").Eq(0)).ToDQL()
+		require.EqualError(t, err, "invalid variable 'v) OR has(x' in len condition")
+	})
+}
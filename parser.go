@@ -0,0 +1,1306 @@
+package dqlx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsedDocument represents a DQL query document converted into query builders.
+// Variable blocks are attached to the first query block
+type ParsedDocument struct {
+	Name       string
+	Parameters []QueryParameter
+	Queries    []QueryBuilder
+}
+
+// QueryParameter represents a GraphQL variable declared by a query document
+//
+// Example: query Users($name: string = "alice")
+type QueryParameter struct {
+	Name       string
+	Type       string
+	Default    string
+	HasDefault bool
+}
+
+// AST returns a read-only representation of the parsed document
+func (document *ParsedDocument) AST() *DocumentNode {
+	return QueriesToAST(document.Queries...)
+}
+
+// ToDQL returns the DQL statement of the parsed document
+func (document *ParsedDocument) ToDQL() (query string, args map[string]string, err error) {
+	return QueriesToDQL(document.Queries...)
+}
+
+// ParseDQL parses a DQL query document into query builders.
+// The values of the GraphQL variables are resolved from the variables map
+// or from the defaults declared by the document.
+// The supported directives are @filter, @facets, @cascade and @groupby,
+// documents using other directives such as @normalize, @recurse or @ignorereflex
+// are rejected as the query builder cannot represent them.
+//
+// Example:
+//   document, err := dqlx.ParseDQL(`
+//     query Users($name: string) {
+//       users(func: eq(name, $name), first: 10) @filter(has(email)) {
+//         uid
+//         name
+//         friends @filter(gt(age, 18)) { name }
+//       }
+//     }
+//   `, map[string]string{"$name": "alice"})
+func ParseDQL(query string, variables map[string]string) (*ParsedDocument, error) {
+	tokens, err := tokenize(query, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &dqlParser{
+		tokens:     tokens,
+		parameters: map[string]QueryParameter{},
+		variables:  variables,
+	}
+
+	return parser.parseDocument()
+}
+
+// ParseQuery parses a DQL query document containing a single query block
+//
+// Example:
+//   query, err := dqlx.ParseQuery(`{ users(func: type(User)) { name } }`, nil)
+func ParseQuery(query string, variables map[string]string) (QueryBuilder, error) {
+	document, err := ParseDQL(query, variables)
+
+	if err != nil {
+		return QueryBuilder{}, err
+	}
+
+	if len(document.Queries) != 1 {
+		return QueryBuilder{}, fmt.Errorf("expected 1 query block, found %d", len(document.Queries))
+	}
+
+	return document.Queries[0], nil
+}
+
+// dqlDirectives the names of the DQL directives, telling them apart from
+// language tags. The parser supports filter, facets, cascade and groupby
+var dqlDirectives = map[string]bool{
+	"filter":       true,
+	"facets":       true,
+	"cascade":      true,
+	"groupby":      true,
+	"normalize":    true,
+	"recurse":      true,
+	"ignorereflex": true,
+}
+
+var selectionFunctions = map[string]bool{
+	"count":  true,
+	"val":    true,
+	"expand": true,
+	"math":   true,
+	"sum":    true,
+	"min":    true,
+	"max":    true,
+	"avg":    true,
+}
+
+type dqlParser struct {
+	tokens     []token
+	current    int
+	parameters map[string]QueryParameter
+	variables  map[string]string
+}
+
+type edgeArguments struct {
+	rootFn     DQLizer
+	pagination Cursor
+	order      []DQLizer
+}
+
+type edgeDirectives struct {
+	filters []DQLizer
+	facets  [][]interface{}
+	cascade DQLizer
+	groupBy []string
+}
+
+func (parser *dqlParser) peek() token {
+	return parser.peekAt(0)
+}
+
+func (parser *dqlParser) peekAt(distance int) token {
+	index := parser.current + distance
+
+	if index >= len(parser.tokens) {
+		return parser.tokens[len(parser.tokens)-1]
+	}
+	return parser.tokens[index]
+}
+
+func (parser *dqlParser) next() token {
+	tok := parser.peek()
+
+	if tok.Type != tokenEOF {
+		parser.current++
+	}
+	return tok
+}
+
+func (parser *dqlParser) is(tokenType tokenType) bool {
+	return parser.peek().Type == tokenType
+}
+
+func (parser *dqlParser) isName(value string) bool {
+	tok := parser.peek()
+	return tok.Type == tokenName && strings.EqualFold(tok.Value, value)
+}
+
+func (parser *dqlParser) expect(tokenType tokenType, description string) (token, error) {
+	tok := parser.peek()
+
+	if tok.Type != tokenType {
+		return tok, parser.unexpected(tok, description)
+	}
+
+	return parser.next(), nil
+}
+
+func (parser *dqlParser) errorf(tok token, format string, args ...interface{}) error {
+	return &ParseError{
+		Pos:     tok.Pos,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (parser *dqlParser) unexpected(tok token, description string) error {
+	if tok.Type == tokenEOF {
+		return parser.errorf(tok, "expected %s, found end of input", description)
+	}
+	return parser.errorf(tok, "expected %s, found '%s'", description, tok.Value)
+}
+
+func (parser *dqlParser) parseDocument() (*ParsedDocument, error) {
+	document := &ParsedDocument{}
+
+	if err := parser.parseHeader(document); err != nil {
+		return nil, err
+	}
+
+	if _, err := parser.expect(tokenLeftCurl, "'{'"); err != nil {
+		return nil, err
+	}
+
+	var variables []QueryBuilder
+
+	for !parser.is(tokenRightCurl) {
+		block, err := parser.parseBlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if block.rootEdge.IsVariable {
+			variables = append(variables, block)
+			continue
+		}
+
+		document.Queries = append(document.Queries, block)
+	}
+
+	if _, err := parser.expect(tokenRightCurl, "'}'"); err != nil {
+		return nil, err
+	}
+
+	if _, err := parser.expect(tokenEOF, "end of input"); err != nil {
+		return nil, err
+	}
+
+	if len(document.Queries) == 0 {
+		return nil, parser.errorf(parser.peek(), "the document doesn't contain any query block")
+	}
+
+	for _, variable := range variables {
+		document.Queries[0] = document.Queries[0].Variable(variable)
+	}
+
+	return document, nil
+}
+
+// parseHeader parses the optional query keyword, name and parameters
+func (parser *dqlParser) parseHeader(document *ParsedDocument) error {
+	if !parser.isName("query") {
+		return nil
+	}
+
+	parser.next()
+
+	if parser.is(tokenName) {
+		document.Name = parser.next().Value
+	}
+
+	if parser.is(tokenLeftParen) {
+		return parser.parseParameters(document)
+	}
+
+	return nil
+}
+
+func (parser *dqlParser) parseParameters(document *ParsedDocument) error {
+	parser.next()
+
+	for !parser.is(tokenRightParen) {
+		nameToken, err := parser.expect(tokenVariable, "a variable declaration")
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := parser.expect(tokenColon, "':'"); err != nil {
+			return err
+		}
+
+		typeToken, err := parser.expect(tokenName, "a variable type")
+
+		if err != nil {
+			return err
+		}
+
+		parameter := QueryParameter{
+			Name: nameToken.Value,
+			Type: strings.ToLower(typeToken.Value),
+		}
+
+		if parser.is(tokenOperator) && parser.peek().Value == "!" {
+			parser.next()
+		}
+
+		if parser.is(tokenOperator) && parser.peek().Value == "=" {
+			parser.next()
+			valueToken := parser.next()
+
+			switch valueToken.Type {
+			case tokenString, tokenNumber, tokenName:
+				parameter.Default = valueToken.Value
+				parameter.HasDefault = true
+			default:
+				return parser.unexpected(valueToken, "a default value")
+			}
+		}
+
+		if _, ok := parser.parameters[parameter.Name]; ok {
+			return parser.errorf(nameToken, "variable %s declared twice", parameter.Name)
+		}
+
+		parser.parameters[parameter.Name] = parameter
+		document.Parameters = append(document.Parameters, parameter)
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+	return nil
+}
+
+func (parser *dqlParser) parseBlock() (QueryBuilder, error) {
+	var alias string
+
+	if parser.is(tokenName) && parser.peekAt(1).Type == tokenName && strings.EqualFold(parser.peekAt(1).Value, "as") {
+		alias = parser.next().Value
+		parser.next()
+	}
+
+	nameToken := parser.next()
+
+	if nameToken.Type != tokenName && nameToken.Type != tokenIRI {
+		return QueryBuilder{}, parser.unexpected(nameToken, "a query block")
+	}
+
+	if !parser.is(tokenLeftParen) {
+		return QueryBuilder{}, parser.unexpected(parser.peek(), "'(' after the block name")
+	}
+
+	arguments, err := parser.parseArguments(true)
+
+	if err != nil {
+		return QueryBuilder{}, err
+	}
+
+	var rootFn *FilterFn
+
+	if arguments.rootFn != nil {
+		rootFn = &FilterFn{arguments.rootFn}
+	}
+
+	var block QueryBuilder
+
+	if nameToken.Type == tokenName && nameToken.Value == "var" {
+		block = Variable(rootFn)
+	} else {
+		block = QueryEdge(nameToken.Value, rootFn)
+	}
+
+	if alias != "" {
+		block = block.As(alias)
+	}
+
+	directives, err := parser.parseDirectives()
+
+	if err != nil {
+		return QueryBuilder{}, err
+	}
+
+	block = applyEdgeParts(block, arguments, directives)
+
+	if _, err := parser.expect(tokenLeftCurl, "'{'"); err != nil {
+		return QueryBuilder{}, err
+	}
+
+	block, selections, err := parser.parseSelectionSet(block, "")
+
+	if err != nil {
+		return QueryBuilder{}, err
+	}
+
+	return block.Select(selections...), nil
+}
+
+func applyEdgeParts(builder QueryBuilder, arguments edgeArguments, directives edgeDirectives) QueryBuilder {
+	if arguments.pagination.WantsPagination() {
+		builder = builder.Paginate(arguments.pagination)
+	}
+
+	for _, order := range arguments.order {
+		builder = builder.Order(order)
+	}
+
+	builder = builder.Filter(directives.filters...)
+
+	for _, facets := range directives.facets {
+		builder = builder.Facets(facets...)
+	}
+
+	if len(directives.groupBy) > 0 {
+		builder = builder.GroupBy(directives.groupBy...)
+	}
+
+	if directives.cascade != nil {
+		builder.rootEdge.Cascade = directives.cascade
+	}
+
+	return builder
+}
+
+func (parser *dqlParser) parseArguments(allowFunc bool) (edgeArguments, error) {
+	arguments := edgeArguments{}
+	parser.next()
+
+	for !parser.is(tokenRightParen) {
+		nameToken, err := parser.expect(tokenName, "an argument name")
+
+		if err != nil {
+			return arguments, err
+		}
+
+		if _, err := parser.expect(tokenColon, "':'"); err != nil {
+			return arguments, err
+		}
+
+		switch strings.ToLower(nameToken.Value) {
+		case "func":
+			if !allowFunc {
+				return arguments, parser.errorf(nameToken, "func is only allowed on query blocks")
+			}
+
+			rootFn, err := parser.parseFunction()
+
+			if err != nil {
+				return arguments, err
+			}
+
+			arguments.rootFn = rootFn
+		case "first", "offset":
+			valueToken := parser.peek()
+			value, err := parser.parseValue()
+
+			if err != nil {
+				return arguments, err
+			}
+
+			number, err := toInt(value)
+
+			if err != nil {
+				return arguments, parser.errorf(valueToken, "%s expects an integer", nameToken.Value)
+			}
+
+			if strings.ToLower(nameToken.Value) == "first" {
+				arguments.pagination.First = number
+			} else {
+				arguments.pagination.Offset = number
+			}
+		case "after":
+			value, err := parser.parseValue()
+
+			if err != nil {
+				return arguments, err
+			}
+
+			arguments.pagination.After = valueToString(value)
+		case "orderasc", "orderdesc":
+			predicate, err := parser.parseOrderPredicate()
+
+			if err != nil {
+				return arguments, err
+			}
+
+			direction := OrderDirection(strings.ToLower(nameToken.Value))
+			arguments.order = append(arguments.order, orderBy{Direction: direction, Predicate: predicate})
+		default:
+			return arguments, parser.errorf(nameToken, "unsupported argument '%s'", nameToken.Value)
+		}
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+	return arguments, nil
+}
+
+func (parser *dqlParser) parseOrderPredicate() (interface{}, error) {
+	if parser.isName("val") && parser.peekAt(1).Type == tokenLeftParen {
+		parser.next()
+		parser.next()
+
+		variableToken := parser.next()
+
+		if variableToken.Type != tokenName && variableToken.Type != tokenIRI {
+			return nil, parser.unexpected(variableToken, "a variable name")
+		}
+
+		if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return Val(variableToken.Value), nil
+	}
+
+	return parser.parsePredicateName()
+}
+
+func (parser *dqlParser) parsePredicateName() (string, error) {
+	predicateToken := parser.next()
+
+	if predicateToken.Type != tokenName && predicateToken.Type != tokenIRI {
+		return "", parser.unexpected(predicateToken, "a predicate")
+	}
+
+	predicate := predicateToken.Value
+
+	if lang := parser.parseLanguage(); lang != "" {
+		predicate += "@" + lang
+	}
+
+	return predicate, nil
+}
+
+// parseLanguage parses a language tag such as @en or @en:fr
+func (parser *dqlParser) parseLanguage() string {
+	if !parser.is(tokenAt) || parser.peekAt(1).Type != tokenName || dqlDirectives[strings.ToLower(parser.peekAt(1).Value)] {
+		return ""
+	}
+
+	parser.next()
+	lang := parser.next().Value
+
+	for parser.is(tokenColon) && parser.peekAt(1).Type == tokenName {
+		parser.next()
+		lang += ":" + parser.next().Value
+	}
+
+	return lang
+}
+
+func (parser *dqlParser) parseDirectives() (edgeDirectives, error) {
+	directives := edgeDirectives{}
+
+	for parser.is(tokenAt) {
+		parser.next()
+		nameToken, err := parser.expect(tokenName, "a directive")
+
+		if err != nil {
+			return directives, err
+		}
+
+		switch strings.ToLower(nameToken.Value) {
+		case "filter":
+			if _, err := parser.expect(tokenLeftParen, "'('"); err != nil {
+				return directives, err
+			}
+
+			filter, err := parser.parseFilter()
+
+			if err != nil {
+				return directives, err
+			}
+
+			if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+				return directives, err
+			}
+
+			// top level AND filters are flattened, the builder joins them with AND
+			if and, ok := filter.(And); ok {
+				directives.filters = append(directives.filters, and...)
+			} else {
+				directives.filters = append(directives.filters, filter)
+			}
+		case "cascade":
+			fields, err := parser.parseNameList()
+
+			if err != nil {
+				return directives, err
+			}
+
+			directives.cascade = Cascade(fields...)
+		case "groupby":
+			fields, err := parser.parseNameList()
+
+			if err != nil {
+				return directives, err
+			}
+
+			directives.groupBy = append(directives.groupBy, fields...)
+		case "facets":
+			facets, err := parser.parseFacets()
+
+			if err != nil {
+				return directives, err
+			}
+
+			directives.facets = append(directives.facets, facets)
+		default:
+			return directives, parser.errorf(nameToken, "unsupported directive '@%s'", nameToken.Value)
+		}
+	}
+
+	return directives, nil
+}
+
+func (parser *dqlParser) parseNameList() ([]string, error) {
+	var names []string
+
+	if !parser.is(tokenLeftParen) {
+		return names, nil
+	}
+
+	parser.next()
+
+	for !parser.is(tokenRightParen) {
+		name, err := parser.parsePredicateName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+	return names, nil
+}
+
+func (parser *dqlParser) parseFacets() ([]interface{}, error) {
+	facets := []interface{}{}
+
+	if !parser.is(tokenLeftParen) {
+		return facets, nil
+	}
+
+	parser.next()
+
+	for !parser.is(tokenRightParen) {
+		switch {
+		case parser.is(tokenName) && parser.peekAt(1).Type == tokenLeftParen:
+			filter, err := parser.parseFilter()
+
+			if err != nil {
+				return nil, err
+			}
+
+			facets = append(facets, filter)
+		case parser.peekAt(1).Type == tokenColon:
+			alias := parser.next().Value
+			parser.next()
+
+			predicate, err := parser.parsePredicateName()
+
+			if err != nil {
+				return nil, err
+			}
+
+			facets = append(facets, Alias(alias, predicate))
+		default:
+			predicate, err := parser.parsePredicateName()
+
+			if err != nil {
+				return nil, err
+			}
+
+			facets = append(facets, predicate)
+		}
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+	return facets, nil
+}
+
+func (parser *dqlParser) parseSelectionSet(block QueryBuilder, parentPath string) (QueryBuilder, []interface{}, error) {
+	var selections []interface{}
+
+	for !parser.is(tokenRightCurl) {
+		if parser.is(tokenEOF) {
+			return block, nil, parser.unexpected(parser.peek(), "'}'")
+		}
+
+		var err error
+		var selection interface{}
+
+		block, selection, err = parser.parseSelection(block, parentPath)
+
+		if err != nil {
+			return block, nil, err
+		}
+
+		if selection != nil {
+			selections = append(selections, selection)
+		}
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+	return block, selections, nil
+}
+
+func (parser *dqlParser) parseSelection(block QueryBuilder, parentPath string) (QueryBuilder, interface{}, error) {
+	var variable string
+	var alias string
+
+	if parser.peekAt(1).Type == tokenName && strings.EqualFold(parser.peekAt(1).Value, "as") && parser.is(tokenName) {
+		variable = parser.next().Value
+		parser.next()
+	}
+
+	if (parser.is(tokenName) || parser.is(tokenIRI)) && parser.peekAt(1).Type == tokenColon {
+		alias = parser.next().Value
+		parser.next()
+	}
+
+	headToken := parser.peek()
+
+	if headToken.Type == tokenName && parser.peekAt(1).Type == tokenLeftParen && selectionFunctions[strings.ToLower(headToken.Value)] {
+		expression, err := parser.parseSelectionFunction()
+
+		if err != nil {
+			return block, nil, err
+		}
+
+		// expand(_all_) { ... } is an edge
+		if strings.HasPrefix(expression, "expand(") && parser.is(tokenLeftCurl) {
+			block, err = parser.parseEdge(block, parentPath, expression, variable, edgeArguments{}, edgeDirectives{})
+			return block, nil, err
+		}
+
+		return block, aliasSelection(Expr(expression), alias, variable), nil
+	}
+
+	predicate, err := parser.parsePredicateName()
+
+	if err != nil {
+		return block, nil, err
+	}
+
+	arguments := edgeArguments{}
+
+	if parser.is(tokenLeftParen) {
+		arguments, err = parser.parseArguments(false)
+
+		if err != nil {
+			return block, nil, err
+		}
+	}
+
+	directivesToken := parser.peek()
+	directives, err := parser.parseDirectives()
+
+	if err != nil {
+		return block, nil, err
+	}
+
+	if !parser.is(tokenLeftCurl) {
+		if len(directives.filters) > 0 || len(directives.groupBy) > 0 || directives.cascade != nil || arguments.pagination.WantsPagination() || len(arguments.order) > 0 {
+			return block, nil, parser.errorf(directivesToken, "expected a selection set for the edge '%s'", predicate)
+		}
+
+		if len(directives.facets) > 0 {
+			expression, args, err := Facets(directives.facets[0]...).ToDQL()
+
+			if err != nil {
+				return block, nil, err
+			}
+
+			if len(args) > 0 {
+				return block, nil, parser.errorf(directivesToken, "facet filters are not supported on predicate '%s'", predicate)
+			}
+
+			return block, aliasSelection(Expr(EscapePredicate(predicate)+" "+expression), alias, variable), nil
+		}
+
+		if alias != "" {
+			predicate = alias + ":" + predicate
+		}
+
+		if variable != "" {
+			predicate = variable + " as " + predicate
+		}
+
+		return block, predicate, nil
+	}
+
+	if alias != "" {
+		return block, nil, parser.errorf(headToken, "aliased edges are not supported, use a variable instead")
+	}
+
+	block, err = parser.parseEdge(block, parentPath, predicate, variable, arguments, directives)
+	return block, nil, err
+}
+
+func (parser *dqlParser) parseEdge(block QueryBuilder, parentPath string, predicate string, variable string, arguments edgeArguments, directives edgeDirectives) (QueryBuilder, error) {
+	fullPath := predicate
+
+	if parentPath != "" {
+		fullPath = EdgePath(parentPath, predicate)
+	}
+
+	parser.next()
+
+	block, selections, err := parser.parseSelectionSet(block, fullPath)
+
+	if err != nil {
+		return block, err
+	}
+
+	return block.EdgeFnAs(variable, fullPath, func(builder QueryBuilder) QueryBuilder {
+		return applyEdgeParts(builder, arguments, directives).Select(selections...)
+	}), nil
+}
+
+func (parser *dqlParser) parseSelectionFunction() (string, error) {
+	nameToken := parser.next()
+	name := strings.ToLower(nameToken.Value)
+	parser.next()
+
+	writer := strings.Builder{}
+	writer.WriteString(name + "(")
+
+	switch name {
+	case "math":
+		depth := 1
+
+		for depth > 0 {
+			tok := parser.next()
+
+			switch tok.Type {
+			case tokenEOF:
+				return "", parser.unexpected(tok, "')'")
+			case tokenLeftParen:
+				depth++
+			case tokenRightParen:
+				depth--
+			case tokenString, tokenIRI, tokenVariable, tokenRegex:
+				return "", parser.errorf(tok, "unsupported value in math expression")
+			}
+
+			if depth > 0 {
+				writer.WriteString(tok.Value)
+			}
+		}
+
+		writer.WriteString(")")
+		return writer.String(), nil
+	case "sum", "min", "max", "avg":
+		if !parser.isName("val") {
+			return "", parser.unexpected(parser.peek(), "val()")
+		}
+
+		inner, err := parser.parseSelectionFunction()
+
+		if err != nil {
+			return "", err
+		}
+
+		writer.WriteString(inner)
+	case "expand":
+		typeToken := parser.next()
+
+		if typeToken.Type != tokenName {
+			return "", parser.unexpected(typeToken, "a type name")
+		}
+
+		writer.WriteString(typeToken.Value)
+	case "val":
+		variableToken := parser.next()
+
+		if variableToken.Type != tokenName {
+			return "", parser.unexpected(variableToken, "a variable name")
+		}
+
+		writer.WriteString(variableToken.Value)
+	case "count":
+		if parser.isName("uid") {
+			parser.next()
+			writer.WriteString("uid")
+			break
+		}
+
+		predicate, err := parser.parsePredicateName()
+
+		if err != nil {
+			return "", err
+		}
+
+		writer.WriteString(EscapePredicate(predicate))
+	}
+
+	if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+		return "", err
+	}
+
+	writer.WriteString(")")
+	return writer.String(), nil
+}
+
+func aliasSelection(expression DQLizer, alias string, variable string) DQLizer {
+	if alias != "" {
+		expression = Alias(alias, expression)
+	}
+
+	if variable != "" {
+		expression = As(variable, expression)
+	}
+
+	return expression
+}
+
+func (parser *dqlParser) parseFilter() (DQLizer, error) {
+	return parser.parseConnective("or", func() (DQLizer, error) {
+		return parser.parseConnective("and", parser.parseNot)
+	})
+}
+
+func (parser *dqlParser) parseConnective(operator string, parseOperand func() (DQLizer, error)) (DQLizer, error) {
+	operand, err := parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []DQLizer{operand}
+
+	for parser.isName(operator) {
+		parser.next()
+		operand, err := parseOperand()
+
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operand, nil
+	}
+
+	if operator == "or" {
+		return Or(operands), nil
+	}
+	return And(operands), nil
+}
+
+func (parser *dqlParser) parseNot() (DQLizer, error) {
+	if parser.isName("not") {
+		parser.next()
+		operand, err := parser.parseNot()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{operand}, nil
+	}
+
+	if parser.is(tokenLeftParen) {
+		parser.next()
+		filter, err := parser.parseFilter()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return filter, nil
+	}
+
+	return parser.parseFunction()
+}
+
+func (parser *dqlParser) parseFunction() (DQLizer, error) {
+	nameToken, err := parser.expect(tokenName, "a function")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parser.expect(tokenLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+
+	funcType := FuncType(strings.ToLower(nameToken.Value))
+
+	switch funcType {
+	case hasFunc, typeFunc:
+		predicate, err := parser.parsePredicateName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+
+		return filterExpr{funcType: funcType, value: Predicate(predicate)}, nil
+	case uidFunc:
+		return parser.parseUIDFunction()
+	}
+
+	var subject interface{}
+
+	if parser.is(tokenName) && parser.peekAt(1).Type == tokenLeftParen {
+		expression, err := parser.parseSelectionFunction()
+
+		if err != nil {
+			return nil, err
+		}
+
+		subject = Expr(expression)
+	} else {
+		predicate, err := parser.parsePredicateName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		subject = predicate
+	}
+
+	var values []interface{}
+
+	for parser.is(tokenComma) {
+		parser.next()
+		value, err := parser.parseValue()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+
+	predicate, isPredicate := subject.(string)
+
+	if !isPredicate {
+		return functionExpr{funcType: funcType, args: append([]interface{}{subject}, values...)}, nil
+	}
+
+	switch {
+	case funcType == betweenFunc && len(values) == 2:
+		return Between(predicate, values[0], values[1]), nil
+	case funcType == regexpFunc && len(values) == 1:
+		if pattern, ok := values[0].(RawExpression); ok {
+			return Regexp{predicate: pattern.Val}, nil
+		}
+		return nil, parser.errorf(nameToken, "regexp expects a regular expression")
+	case len(values) == 1:
+		if filter, ok := kvFilter(funcType, predicate, values[0]); ok {
+			return filter, nil
+		}
+	}
+
+	return functionExpr{funcType: funcType, args: append([]interface{}{Predicate(predicate)}, values...)}, nil
+}
+
+func (parser *dqlParser) parseUIDFunction() (DQLizer, error) {
+	var values []interface{}
+
+	for !parser.is(tokenRightParen) {
+		tok := parser.peek()
+
+		switch {
+		case tok.Type == tokenName && strings.EqualFold(tok.Value, "val") && parser.peekAt(1).Type == tokenLeftParen:
+			expression, err := parser.parseSelectionFunction()
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, Expr(expression))
+		case tok.Type == tokenName:
+			parser.next()
+			values = append(values, Expr(tok.Value))
+		case tok.Type == tokenIRI:
+			parser.next()
+			values = append(values, Predicate(tok.Value))
+		default:
+			value, err := parser.parseValue()
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, valueToString(value))
+		}
+
+		if parser.is(tokenComma) {
+			parser.next()
+		}
+	}
+
+	parser.next()
+
+	if len(values) == 1 {
+		return UID(values[0]), nil
+	}
+
+	return functionExpr{funcType: uidFunc, args: values}, nil
+}
+
+// parseUIDValue parses the uid(...) value of uid_in,
+// the uids are variables or literal uids
+func (parser *dqlParser) parseUIDValue() (interface{}, error) {
+	parser.next()
+	var uids []string
+
+	for !parser.is(tokenRightParen) {
+		tok, err := parser.expect(tokenName, "a variable or a uid")
+
+		if err != nil {
+			return nil, err
+		}
+
+		uids = append(uids, tok.Value)
+
+		if !parser.is(tokenComma) {
+			break
+		}
+
+		parser.next()
+	}
+
+	if _, err := parser.expect(tokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+
+	return Expr("uid(" + strings.Join(uids, ",") + ")"), nil
+}
+
+func (parser *dqlParser) parseValue() (interface{}, error) {
+	tok := parser.next()
+
+	switch tok.Type {
+	case tokenString:
+		return tok.Value, nil
+	case tokenNumber:
+		return parseNumber(tok.Value), nil
+	case tokenRegex:
+		return Expr(tok.Value), nil
+	case tokenVariable:
+		return parser.resolveVariable(tok)
+	case tokenName:
+		switch strings.ToLower(tok.Value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+
+		if strings.EqualFold(tok.Value, "uid") && parser.is(tokenLeftParen) {
+			return parser.parseUIDValue()
+		}
+
+		if parser.is(tokenLeftParen) {
+			parser.current--
+			expression, err := parser.parseSelectionFunction()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return Expr(expression), nil
+		}
+
+		return Expr(tok.Value), nil
+	case tokenLeftSquare:
+		values := []interface{}{}
+
+		for !parser.is(tokenRightSquare) {
+			value, err := parser.parseValue()
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+
+			if parser.is(tokenComma) {
+				parser.next()
+			}
+		}
+
+		parser.next()
+		return values, nil
+	}
+
+	return nil, parser.unexpected(tok, "a value")
+}
+
+func (parser *dqlParser) resolveVariable(tok token) (interface{}, error) {
+	parameter, ok := parser.parameters[tok.Value]
+
+	if !ok {
+		return nil, parser.errorf(tok, "variable %s is not declared", tok.Value)
+	}
+
+	value, ok := parser.variables[tok.Value]
+
+	if !ok {
+		if !parameter.HasDefault {
+			return nil, parser.errorf(tok, "missing value for variable %s", tok.Value)
+		}
+
+		value = parameter.Default
+	}
+
+	converted, err := convertParameter(value, parameter.Type)
+
+	if err != nil {
+		return nil, parser.errorf(tok, "invalid value for variable %s: %s", tok.Value, err.Error())
+	}
+
+	return converted, nil
+}
+
+func convertParameter(value string, dqlType string) (interface{}, error) {
+	switch dqlType {
+	case "int":
+		return strconv.Atoi(value)
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	case "bool":
+		return strconv.ParseBool(value)
+	case "datetime":
+		return time.Parse(time.RFC3339, value)
+	}
+
+	return value, nil
+}
+
+func parseNumber(value string) interface{} {
+	if number, err := strconv.Atoi(value); err == nil {
+		return number
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+
+	// uids such as 0x1
+	return value
+}
+
+func toInt(value interface{}) (int, error) {
+	switch cast := value.(type) {
+	case int:
+		return cast, nil
+	case string:
+		return strconv.Atoi(cast)
+	}
+
+	return 0, fmt.Errorf("%v is not an integer", value)
+}
+
+func valueToString(value interface{}) string {
+	if expression, ok := value.(RawExpression); ok {
+		return expression.Val
+	}
+	return toVariableValue(value)
+}
+
+func kvFilter(funcType FuncType, predicate string, value interface{}) (DQLizer, bool) {
+	return newFilterKV(funcType, filterKV{predicate: value})
+}
+
+// functionExpr represents a generic function call,
+// used for functions without a dedicated expression
+type functionExpr struct {
+	funcType FuncType
+	args     []interface{}
+}
+
+// ToDQL returns the DQL statement for a generic function call
+func (function functionExpr) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(function)
+}
+
+func (function functionExpr) renderDQL(writer *dqlWriter) error {
+	writer.WriteString(string(function.funcType))
+	writer.WriteString("(")
+
+	for index, arg := range function.args {
+		if index > 0 {
+			writer.WriteString(",")
+		}
+
+		if err := writer.writeValue(arg); err != nil {
+			return err
+		}
+	}
+
+	writer.WriteString(")")
+	return nil
+}
@@ -0,0 +1,239 @@
+package dqlx_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse_Simple_Query(t *testing.T) {
+	document, err := dql.ParseDQL(`
+		query Users($name: string, $age: int = 18) {
+			users(func: eq(name@en, $name), first: 10, offset: 5) @filter(gt(age, $age) AND has(email)) {
+				uid
+				name
+				email
+			}
+		}
+	`, map[string]string{
+		"$name": "alice",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "Users", document.Name)
+	require.Equal(t, []dql.QueryParameter{
+		{Name: "$name", Type: "string"},
+		{Name: "$age", Type: "int", Default: "18", HasDefault: true},
+	}, document.Parameters)
+
+	query, variables, err := document.ToDQL()
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"$0": "alice",
+		"$1": "10",
+		"$2": "5",
+		"$3": "18",
+	}, variables)
+
+	expected := dql.Minify(`
+		query Users($0:string, $1:int, $2:int, $3:int) {
+			<users>(func: eq(<name>@en,$0),first:$1,offset:$2) @filter(gt(<age>,$3) AND has(<email>)) {
+				<uid>
+				<name>
+				<email>
+			}
+		}
+	`)
+
+	require.Equal(t, expected, query)
+}
+
+func Test_Parse_Round_Trip(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	variable := dql.Variable(dql.EqFn("name", "test")).
+		Edge("film", dql.Fields(`
+			D as initial_release_date
+		`)).
+		Edge("film->performance", dql.Fields(`
+			uid
+		`))
+
+	builders := map[string]dql.QueryBuilder{
+		"nested": dql.
+			QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+			Fields(`
+				uid
+				name
+			`).
+			Edge("actors", dql.Fields(`
+				uid
+				surname
+			`), dql.Cursor{First: 10, Offset: 2}).
+			Edge("actors->rewards", dql.Fields(`
+				points
+			`), dql.OrderDesc("points")).
+			Edge("actors->rewards->venues", dql.Fields(`
+				street
+			`)),
+		"connecting filters": dql.
+			QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+			Fields(`
+				uid
+			`).
+			Filter(dql.Or{
+				dql.Eq{"name": "actor1"},
+				dql.Eq{"name": "actor2"},
+			}).
+			Edge("authors", dql.Fields(`
+				name
+			`), dql.Or{
+				dql.And{
+					dql.Eq{"name": "author3"},
+					dql.Gt{"age": 20},
+				},
+				dql.And{
+					dql.Eq{"name": "author4"},
+					dql.Lt{"age": 50.5},
+				},
+			}),
+		"variables": dql.
+			QueryEdge("bladerunner", dql.EqFn("item", "value")).
+			Fields(`
+				uid
+				name
+			`).
+			Variable(variable).
+			Filter(dql.UID(dql.Expr("D"))).
+			Filter(dql.UIDIn{"friends": dql.Expr("uid(D)")}).
+			OrderAsc(dql.Val("D")),
+		"functions": dql.
+			QueryEdge("bladerunner", dql.UIDFn("0x1")).
+			Fields(`
+				uid
+			`).
+			Filter(dql.UIDIn{"name": "value1"}).
+			Filter(dql.Between("release_date", from, to)).
+			Filter(dql.Regexp{"name": "/^Steven.*$/i"}).
+			Filter(dql.Not{dql.Eq{"deleted": true}}),
+		"directives": dql.
+			QueryEdge("bladerunner", dql.TypeFn("Film")).
+			Fields(`
+				uid
+			`).
+			Cascade().
+			Facets().
+			Edge("films", dql.Fields(`
+				date
+			`), dql.Cascade("date", "id"), dql.Facets("relative"), dql.GroupBy("genre")),
+		"aggregations": dql.
+			QueryEdge("bladerunner", dql.TypeFn("Film")).
+			Fields(
+				"uid",
+				"title:name@en",
+				dql.Alias("total", dql.Count("actors")),
+				dql.As("A", dql.Count("uid")),
+			),
+	}
+
+	for name, builder := range builders {
+		t.Run(name, func(t *testing.T) {
+			query, variables, err := builder.ToDQL()
+			require.NoError(t, err)
+
+			document, err := dql.ParseDQL(query, variables)
+			require.NoError(t, err)
+
+			parsedQuery, parsedVariables, err := document.ToDQL()
+			require.NoError(t, err)
+
+			require.Equal(t, query, parsedQuery)
+			require.Equal(t, variables, parsedVariables)
+		})
+	}
+}
+
+func Test_Parse_UID_In(t *testing.T) {
+	query, err := dql.ParseQuery(`{ users(func: type(User)) @filter(uid_in(friends, uid(A, B)) OR uid_in(friends, 0x1)) { name } }`, nil)
+	require.NoError(t, err)
+
+	dqlQuery, variables, err := query.ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, dqlQuery, "@filter((uid_in(<friends>,uid(A,B)) OR uid_in(<friends>,$0)))")
+	require.Equal(t, map[string]string{"$0": "0x1"}, variables)
+}
+
+func Test_Parse_Query(t *testing.T) {
+	query, err := dql.ParseQuery(`{ users(func: type(User)) { name } }`, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "users", query.GetName())
+
+	_, err = dql.ParseQuery(`{ a(func: type(A)) { name } b(func: type(B)) { name } }`, nil)
+	require.EqualError(t, err, "expected 1 query block, found 2")
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	cases := map[string]struct {
+		query     string
+		variables map[string]string
+		expected  string
+	}{
+		"missing brace": {
+			query:    "{\n  users(func: type(User)) {\n    name\n",
+			expected: "line 4, column 1: expected '}', found end of input",
+		},
+		"undeclared variable": {
+			query:    "{\n  users(func: eq(name, $name)) { name }\n}",
+			expected: "line 2, column 24: variable $name is not declared",
+		},
+		"missing variable": {
+			query:    "query q($name: string) {\n  users(func: eq(name, $name)) { name }\n}",
+			expected: "line 2, column 24: missing value for variable $name",
+		},
+		"invalid variable": {
+			query:     "query q($age: int) {\n  users(func: eq(age, $age)) { name }\n}",
+			variables: map[string]string{"$age": "old"},
+			expected:  `line 2, column 23: invalid value for variable $age: strconv.Atoi: parsing "old": invalid syntax`,
+		},
+		"unsupported directive": {
+			query:    "{\n  users(func: type(User)) @normalize { name }\n}",
+			expected: "line 2, column 28: unsupported directive '@normalize'",
+		},
+		"unsupported edge directive": {
+			query:    "{\n  users(func: type(User)) { friends @normalize { name } }\n}",
+			expected: "line 2, column 38: unsupported directive '@normalize'",
+		},
+		"recurse": {
+			query:    "{\n  users(func: type(User)) @recurse(depth: 3) { name }\n}",
+			expected: "line 2, column 28: unsupported directive '@recurse'",
+		},
+		"ignorereflex": {
+			query:    "{\n  users(func: type(User)) @ignorereflex { name }\n}",
+			expected: "line 2, column 28: unsupported directive '@ignorereflex'",
+		},
+		"uid_in without uid": {
+			query:    "{\n  users(func: type(User)) @filter(uid_in(friends, uid(\"a\"))) { name }\n}",
+			expected: "line 2, column 55: expected a variable or a uid, found 'a'",
+		},
+		"unterminated string": {
+			query:    "{\n  users(func: eq(name, \"alice)) { name }\n}",
+			expected: "line 2, column 24: unterminated string",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := dql.ParseDQL(test.query, test.variables)
+
+			var parseError *dql.ParseError
+			require.True(t, errors.As(err, &parseError))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
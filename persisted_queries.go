@@ -0,0 +1,180 @@
+package dqlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// QueryAllowlist holds the approved shapes of queries, keyed by the hash of their
+// DQL template. Values, inlined or sent as variables, don't change the hash.
+// An executor configured with WithQueryAllowlist refuses any other query.
+//
+// The allowlist is usually recorded while running the test suite, exported,
+// and loaded by the service at startup:
+//
+// Example:
+//   // tests
+//   allowlist := dqlx.NewQueryAllowlist().Record(true)
+//   ... execute queries with dqlx.WithQueryAllowlist(allowlist)
+//   allowlist.Export(file)
+//
+//   // service
+//   allowlist, err := dqlx.LoadQueryAllowlist(file)
+//   db.QueryType("User").Execute(ctx, dqlx.WithQueryAllowlist(allowlist))
+type QueryAllowlist struct {
+	mutex     sync.RWMutex
+	queries   map[string]string
+	recording bool
+}
+
+// QueryNotAllowedError is returned when a query is not in the allowlist
+type QueryNotAllowedError struct {
+	Hash  string
+	Query string
+}
+
+// Error returns the error message
+func (err *QueryNotAllowedError) Error() string {
+	return fmt.Sprintf("query %s is not in the allowlist", err.Hash)
+}
+
+// QueryHash returns the hash identifying the shape of a DQL document,
+// it is the hash of its Fingerprint: values, list lengths and whitespace
+// are not significant. Documents that cannot be parsed are hashed as they are
+func QueryHash(query string) string {
+	template, err := queryTemplate(query)
+
+	if err != nil {
+		return hashTemplate(Minify(query))
+	}
+
+	return hashTemplate(template)
+}
+
+// NewQueryAllowlist creates an empty QueryAllowlist
+func NewQueryAllowlist() *QueryAllowlist {
+	return &QueryAllowlist{
+		queries: map[string]string{},
+	}
+}
+
+// LoadQueryAllowlist loads an allowlist exported with Export,
+// the hash of every query is verified
+func LoadQueryAllowlist(reader io.Reader) (*QueryAllowlist, error) {
+	var queries map[string]string
+
+	if err := json.NewDecoder(reader).Decode(&queries); err != nil {
+		return nil, fmt.Errorf("invalid query allowlist: %w", err)
+	}
+
+	allowlist := NewQueryAllowlist()
+
+	for hash, query := range queries {
+		if QueryHash(query) != hash {
+			return nil, fmt.Errorf("invalid query allowlist: the hash of query %s doesn't match", hash)
+		}
+
+		allowlist.queries[hash] = Minify(query)
+	}
+
+	return allowlist, nil
+}
+
+// Record enables or disables the record mode,
+// queries executed while recording are added to the allowlist instead of being refused
+func (allowlist *QueryAllowlist) Record(enabled bool) *QueryAllowlist {
+	allowlist.mutex.Lock()
+	defer allowlist.mutex.Unlock()
+
+	allowlist.recording = enabled
+	return allowlist
+}
+
+// Allow adds the shape of the queries to the allowlist and returns its hash
+func (allowlist *QueryAllowlist) Allow(queries ...QueryBuilder) (string, error) {
+	query, _, err := QueriesToDQL(queries...)
+
+	if err != nil {
+		return "", err
+	}
+
+	return allowlist.AllowDQL(query), nil
+}
+
+// AllowDQL adds a DQL document to the allowlist and returns its hash
+func (allowlist *QueryAllowlist) AllowDQL(query string) string {
+	allowlist.mutex.Lock()
+	defer allowlist.mutex.Unlock()
+
+	hash := QueryHash(query)
+	allowlist.queries[hash] = Minify(query)
+
+	return hash
+}
+
+// IsAllowed determines if the DQL document is in the allowlist
+func (allowlist *QueryAllowlist) IsAllowed(query string) bool {
+	allowlist.mutex.RLock()
+	defer allowlist.mutex.RUnlock()
+
+	_, ok := allowlist.queries[QueryHash(query)]
+	return ok
+}
+
+// Hashes returns the hashes of the allowed queries in alphabetical order
+func (allowlist *QueryAllowlist) Hashes() []string {
+	allowlist.mutex.RLock()
+	defer allowlist.mutex.RUnlock()
+
+	hashes := make([]string, 0, len(allowlist.queries))
+
+	for hash := range allowlist.queries {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Strings(hashes)
+	return hashes
+}
+
+// Export writes the allowlist as a JSON object of hashes and queries
+func (allowlist *QueryAllowlist) Export(writer io.Writer) error {
+	allowlist.mutex.RLock()
+	defer allowlist.mutex.RUnlock()
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(allowlist.queries)
+}
+
+// check refuses queries not in the allowlist, unless recording
+func (allowlist *QueryAllowlist) check(query string) error {
+	if allowlist.IsAllowed(query) {
+		return nil
+	}
+
+	allowlist.mutex.RLock()
+	recording := allowlist.recording
+	allowlist.mutex.RUnlock()
+
+	if recording {
+		allowlist.AllowDQL(query)
+		return nil
+	}
+
+	return &QueryNotAllowedError{
+		Hash:  QueryHash(query),
+		Query: query,
+	}
+}
+
+// WithQueryAllowlist refuses to execute queries that are not in the allowlist
+func WithQueryAllowlist(allowlist *QueryAllowlist) OperationExecutorOptionFn {
+	return func(executor *OperationExecutor) {
+		executor.allowlist = allowlist
+	}
+}
@@ -0,0 +1,105 @@
+package dqlx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAllowlist(t *testing.T) {
+	const errMissingClient = "cannot execute query without setting a dqlx. use DClient() to set one"
+
+	usersByName := func(name string) dql.QueryBuilder {
+		return dql.QueryType("User").Filter(dql.Eq{"name": name}).Select("uid name")
+	}
+
+	t.Run("values don't change the hash", func(t *testing.T) {
+		allowlist := dql.NewQueryAllowlist()
+
+		hash, err := allowlist.Allow(usersByName("alice"))
+		require.NoError(t, err)
+		require.Len(t, hash, 64)
+
+		query, _, err := usersByName("bob").ToDQL()
+		require.NoError(t, err)
+		require.True(t, allowlist.IsAllowed(query))
+		require.Equal(t, hash, dql.QueryHash(query))
+
+		query, _, err = usersByName("bob").Select("email").ToDQL()
+		require.NoError(t, err)
+		require.False(t, allowlist.IsAllowed(query))
+	})
+
+	t.Run("list lengths don't change the hash", func(t *testing.T) {
+		allowlist := dql.NewQueryAllowlist()
+
+		hash, err := allowlist.Allow(dql.QueryType("User").Filter(dql.Eq{"status": []string{"a"}}).Select("uid"))
+		require.NoError(t, err)
+
+		query, _, err := dql.QueryType("User").Filter(dql.Eq{"status": []string{"a", "b", "c"}}).Select("uid").ToDQL()
+		require.NoError(t, err)
+		require.True(t, allowlist.IsAllowed(query))
+		require.Equal(t, hash, dql.QueryHash(query))
+	})
+
+	t.Run("inlined values don't change the hash", func(t *testing.T) {
+		require.Equal(t,
+			dql.QueryHash("{ q(func: uid(0x1)) @filter(regexp(name, /^a/) AND eq(age, 1)) { uid } }"),
+			dql.QueryHash("{ q(func: uid(0x1)) @filter(regexp(name, /^b.*/i) AND eq(age, 2)) { uid } }"),
+		)
+	})
+
+	t.Run("whitespace is not significant", func(t *testing.T) {
+		require.Equal(t, dql.QueryHash("{ q(func: uid(0x1)) { uid } }"), dql.QueryHash("{\n  q(func: uid(0x1)) {\n    uid\n  }\n}"))
+	})
+
+	t.Run("executor refuses unknown queries", func(t *testing.T) {
+		allowlist := dql.NewQueryAllowlist()
+
+		_, err := usersByName("alice").Execute(context.Background(), dql.WithQueryAllowlist(allowlist))
+
+		var notAllowed *dql.QueryNotAllowedError
+		require.True(t, errors.As(err, &notAllowed))
+		require.Contains(t, notAllowed.Query, "eq(<name>,$0)")
+		require.EqualError(t, err, "query "+notAllowed.Hash+" is not in the allowlist")
+
+		executor := dql.NewDGoExecutor(nil)
+		dql.WithQueryAllowlist(allowlist)(executor)
+
+		_, err = executor.ExecuteDQL(context.Background(), "{ q(func: uid(0x1)) { uid } }", nil)
+		require.True(t, errors.As(err, &notAllowed))
+	})
+
+	t.Run("record and export", func(t *testing.T) {
+		allowlist := dql.NewQueryAllowlist().Record(true)
+
+		// the query is recorded, the execution then fails without a client
+		_, err := usersByName("alice").Execute(context.Background(), dql.WithQueryAllowlist(allowlist))
+		require.EqualError(t, err, errMissingClient)
+		require.Len(t, allowlist.Hashes(), 1)
+
+		buffer := &bytes.Buffer{}
+		require.NoError(t, allowlist.Export(buffer))
+		require.Contains(t, buffer.String(), "eq(<name>,$0)")
+
+		loaded, err := dql.LoadQueryAllowlist(buffer)
+		require.NoError(t, err)
+		require.Equal(t, allowlist.Hashes(), loaded.Hashes())
+
+		_, err = usersByName("bob").Execute(context.Background(), dql.WithQueryAllowlist(loaded))
+		require.EqualError(t, err, errMissingClient)
+	})
+
+	t.Run("load verifies hashes", func(t *testing.T) {
+		_, err := dql.LoadQueryAllowlist(strings.NewReader(`{"abc": "{ q(func: uid(0x1)) { uid } }"}`))
+		require.EqualError(t, err, "invalid query allowlist: the hash of query abc doesn't match")
+
+		_, err = dql.LoadQueryAllowlist(strings.NewReader(`[]`))
+		require.Error(t, err)
+	})
+}
@@ -200,6 +200,10 @@ func (builder *PredicateDateBuilder) IndexHour() *PredicateDateBuilder {
 // EscapePredicate safely escape a predicate
 // Example: dqlx.EscapePredicate("predicate")
 func EscapePredicate(field string) string {
+	if isPlainPredicate(field) {
+		return "<" + field + ">"
+	}
+
 	field = Minify(field)
 	field = escapeSpecialChars(field)
 	parts := strings.Fields(field)
@@ -239,6 +243,27 @@ func EscapePredicate(field string) string {
 	return fmt.Sprintf("%s<%s>%s", alias, field, directive)
 }
 
+// isPlainPredicate determines if the predicate is a plain name,
+// without alias, directive or characters to escape
+func isPlainPredicate(predicate string) bool {
+	if predicate == "" {
+		return false
+	}
+
+	for index := 0; index < len(predicate); index++ {
+		char := predicate[index]
+
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case char == '_', char == '.', char == '-', char == '~':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
 func escapeSpecialChars(predicate string) string {
 	escapeCharacters := []string{"^", "}", "|", "{", "\\", ",", "<", ">", "\""}
 
@@ -0,0 +1,193 @@
+package dqlx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	dgo "github.com/dgraph-io/dgo/v200"
+)
+
+// Parameter a value provided when a prepared query is executed
+type Parameter struct {
+	Name string
+	Type DGraphScalar
+}
+
+// Param returns a parameter of a prepared query, usable in place of any value
+//
+// Example:
+//   dqlx.Query(dqlx.EqFn("email", dqlx.Param("email", dqlx.ScalarString))).
+//     Paginate(dqlx.Cursor{First: 10}).
+//     Filter(dqlx.Gt{"age": dqlx.Param("age", dqlx.ScalarInt)})
+func Param(name string, scalar DGraphScalar) Parameter {
+	return Parameter{Name: name, Type: scalar}
+}
+
+// variableType returns the type of the GraphQL variable holding the parameter
+func (parameter Parameter) variableType() string {
+	switch parameter.Type {
+	case ScalarInt, ScalarFloat, ScalarBool, ScalarDateTime:
+		return string(parameter.Type)
+	}
+
+	return string(ScalarString)
+}
+
+// PreparedQuery a query rendered once and executed with different parameters.
+// Values that are not parameters are bound when the query is prepared.
+// A PreparedQuery is immutable and safe for concurrent use
+type PreparedQuery struct {
+	query       string
+	variables   map[string]string
+	parameters  map[string][]string
+	types       map[string]string
+	dataKeyPath string
+	queries     []QueryBuilder
+	client      *dgo.Dgraph
+}
+
+// Prepare renders the queries once, values are provided with Param
+// and bound on every execution. The result set is not unmarshalled into
+// the UnmarshalInto value of the queries, use Decode or Response.Unmarshal
+//
+// Example:
+//   prepared, err := dqlx.Prepare(db.Query(dqlx.EqFn("email", dqlx.Param("email", dqlx.ScalarString))))
+//
+//   response, err := prepared.Execute(ctx, map[string]interface{}{"email": email}, dqlx.WithReadOnly(true))
+//   users, err := dqlx.Decode[[]User](response)
+func Prepare(queries ...QueryBuilder) (*PreparedQuery, error) {
+	operation, err := newQueryOperation(queries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := operation.render()
+
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := &PreparedQuery{
+		parameters: map[string][]string{},
+		types:      map[string]string{},
+		queries:    append([]QueryBuilder{}, queries...),
+	}
+
+	variables, placeholders := toVariables(writer.args)
+
+	for index, arg := range writer.args {
+		parameter, ok := arg.(Parameter)
+
+		if !ok {
+			continue
+		}
+
+		variableType := parameter.variableType()
+
+		if declared, ok := prepared.types[parameter.Name]; ok && declared != variableType {
+			return nil, fmt.Errorf("parameter %s is used as %s and %s", parameter.Name, declared, variableType)
+		}
+
+		slot := fmt.Sprintf("$%d", index)
+		delete(variables, slot)
+
+		prepared.types[parameter.Name] = variableType
+		prepared.parameters[parameter.Name] = append(prepared.parameters[parameter.Name], slot)
+	}
+
+	prepared.query = operation.document(placeholders, writer)
+	prepared.variables = variables
+
+	if len(queries) == 1 {
+		prepared.dataKeyPath = queries[0].rootEdge.Name
+	}
+
+	if len(queries) > 0 {
+		prepared.client = queries[0].client
+	}
+
+	return prepared, nil
+}
+
+// Query returns the DQL document of the prepared query
+func (prepared *PreparedQuery) Query() string {
+	return prepared.query
+}
+
+// Parameters returns the names of the parameters in alphabetical order
+func (prepared *PreparedQuery) Parameters() []string {
+	names := make([]string, 0, len(prepared.parameters))
+
+	for name := range prepared.parameters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Bind returns the GraphQL variables of an execution,
+// a value must be provided for every parameter
+func (prepared *PreparedQuery) Bind(parameters map[string]interface{}) (map[string]string, error) {
+	for name := range parameters {
+		if _, ok := prepared.parameters[name]; !ok {
+			return nil, fmt.Errorf("the prepared query has no parameter %s", name)
+		}
+	}
+
+	variables := make(map[string]string, len(prepared.variables)+len(parameters))
+
+	for name, value := range prepared.variables {
+		variables[name] = value
+	}
+
+	for _, name := range prepared.Parameters() {
+		value, ok := parameters[name]
+
+		if !ok {
+			return nil, fmt.Errorf("missing value for parameter %s", name)
+		}
+
+		formatted, err := formatParameter(prepared.types[name], value)
+
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+
+		for _, slot := range prepared.parameters[name] {
+			variables[slot] = formatted
+		}
+	}
+
+	return variables, nil
+}
+
+// Execute binds the parameters and executes the prepared query
+func (prepared *PreparedQuery) Execute(ctx context.Context, parameters map[string]interface{}, options ...OperationExecutorOptionFn) (*Response, error) {
+	variables, err := prepared.Bind(parameters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	executor := NewDGoExecutor(prepared.client)
+
+	for _, option := range options {
+		option(executor)
+	}
+
+	if err := executor.checkLimits(prepared.queries...); err != nil {
+		return nil, err
+	}
+
+	response, err := executor.executeDQL(ctx, prepared.query, variables)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response.dataKeyPath = prepared.dataKeyPath
+	return response, nil
+}
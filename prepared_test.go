@@ -0,0 +1,95 @@
+package dqlx_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreparedQuery(t *testing.T) {
+	query := dql.Query(dql.EqFn("email", dql.Param("email", dql.ScalarString))).
+		Paginate(dql.Cursor{First: 10}).
+		Filter(dql.Or{
+			dql.Gt{"age": dql.Param("age", dql.ScalarInt)},
+			dql.Eq{"nickname": dql.Param("email", dql.ScalarString)},
+		}).
+		Edge("posts", dql.Select("title"), dql.Ge{"created_at": dql.Param("since", dql.ScalarDateTime)})
+
+	prepared, err := dql.Prepare(query)
+	require.NoError(t, err)
+
+	require.Equal(t, "query Rootquery($0:string, $1:int, $2:int, $3:string, $4:datetime) { <rootQuery>(func: eq(<email>,$0),first:$1) @filter((gt(<age>,$2) OR eq(<nickname>,$3))) { <posts> @filter(ge(<created_at>,$4)) { <title> } } }", prepared.Query())
+	require.Equal(t, []string{"age", "email", "since"}, prepared.Parameters())
+
+	t.Run("bind", func(t *testing.T) {
+		variables, err := prepared.Bind(map[string]interface{}{
+			"email": "alice@example.com",
+			"age":   18,
+			"since": time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"$0": "alice@example.com",
+			"$1": "10",
+			"$2": "18",
+			"$3": "alice@example.com",
+			"$4": "2021-01-02T03:04:05Z",
+		}, variables)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := prepared.Bind(map[string]interface{}{"email": "a", "age": 1})
+		require.EqualError(t, err, "missing value for parameter since")
+
+		_, err = prepared.Bind(map[string]interface{}{"email": "a", "age": 1, "since": time.Now(), "name": "b"})
+		require.EqualError(t, err, "the prepared query has no parameter name")
+
+		_, err = prepared.Bind(map[string]interface{}{"email": "a", "age": "1", "since": time.Now()})
+		require.EqualError(t, err, "parameter age: expected a value of type int, given string")
+	})
+
+	t.Run("concurrent executions", func(t *testing.T) {
+		var wait sync.WaitGroup
+
+		for index := 0; index < 20; index++ {
+			wait.Add(1)
+
+			go func(age int) {
+				defer wait.Done()
+
+				variables, err := prepared.Bind(map[string]interface{}{"email": "a", "age": age, "since": time.Now()})
+				require.NoError(t, err)
+				require.Equal(t, "10", variables["$1"])
+			}(index)
+		}
+
+		wait.Wait()
+	})
+
+	t.Run("executor options", func(t *testing.T) {
+		parameters := map[string]interface{}{"email": "a", "age": 1, "since": time.Now()}
+
+		_, err := prepared.Execute(context.Background(), parameters, dql.WithReadOnly(true))
+		require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+
+		_, err = prepared.Execute(context.Background(), parameters, dql.WithQueryLimits(dql.QueryLimits{MaxDepth: 0, RequirePagination: true}))
+		require.EqualError(t, err, "query limit exceeded in rootQuery->posts: pagination is required")
+	})
+
+	t.Run("parameters require a prepared query", func(t *testing.T) {
+		_, _, err := query.ToDQL()
+		require.EqualError(t, err, "parameter email can only be used in prepared queries")
+	})
+
+	t.Run("conflicting types", func(t *testing.T) {
+		_, err := dql.Prepare(dql.Query(dql.EqFn("age", dql.Param("value", dql.ScalarInt))).
+			Filter(dql.Eq{"name": dql.Param("value", dql.ScalarString)}))
+
+		require.EqualError(t, err, "parameter value is used as int and string")
+	})
+}
@@ -14,6 +14,8 @@ type QueryBuilder struct {
 	variables     []QueryBuilder
 	childrenEdges map[string][]QueryBuilder
 	unmarshalInto interface{}
+	scopes        []string
+	strict        strictness
 
 	client *dgo.Dgraph
 }
@@ -107,7 +109,7 @@ func (builder QueryBuilder) ToDQL() (query string, args map[string]string, err e
 // Example:
 //   dqlx.Query(...).Variable(variable)
 func (builder QueryBuilder) Variable(queryBuilder QueryBuilder) QueryBuilder {
-	builder.variables = append(builder.variables, queryBuilder)
+	builder.variables = appendQueries(builder.variables, queryBuilder)
 	return builder
 }
 
@@ -151,7 +153,7 @@ func (builder QueryBuilder) Fields(predicates ...interface{}) QueryBuilder {
 //   dqlx.Query(...).Facets("field1")
 //   dqlx.Query(...).Facets(dqlx.Eq{"field1": "value"})
 func (builder QueryBuilder) Facets(predicates ...interface{}) QueryBuilder {
-	builder.rootEdge.Facets = append(builder.rootEdge.Facets, facetExpr{
+	builder.rootEdge.Facets = appendDQLizers(builder.rootEdge.Facets, facetExpr{
 		Predicates: predicates,
 	})
 
@@ -164,7 +166,7 @@ func (builder QueryBuilder) Facets(predicates ...interface{}) QueryBuilder {
 //   dqlx.Query(...).Order(dqlx.OrderAsc("field1"))
 //   dqlx.Query(...).Order(dqlx.OrderDesc("field2"))
 func (builder QueryBuilder) Order(order DQLizer) QueryBuilder {
-	builder.rootEdge.Order = append(builder.rootEdge.Order, order)
+	builder.rootEdge.Order = appendDQLizers(builder.rootEdge.Order, order)
 	return builder
 }
 
@@ -174,7 +176,7 @@ func (builder QueryBuilder) Order(order DQLizer) QueryBuilder {
 //   dqlx.Query(...).OrderAsc("field1")
 //   dqlx.Query(...).Order(dqlx.OrderAsc("field1")) // equivalent
 func (builder QueryBuilder) OrderAsc(predicate interface{}) QueryBuilder {
-	builder.rootEdge.Order = append(builder.rootEdge.Order, orderBy{
+	builder.rootEdge.Order = appendDQLizers(builder.rootEdge.Order, orderBy{
 		Direction: OrderDirectionAsc,
 		Predicate: predicate,
 	})
@@ -187,7 +189,7 @@ func (builder QueryBuilder) OrderAsc(predicate interface{}) QueryBuilder {
 //   dqlx.Query(...).OrderDesc("field1")
 //   dqlx.Query(...).Order(dqlx.OrderDesc("field1")) // equivalent
 func (builder QueryBuilder) OrderDesc(predicate interface{}) QueryBuilder {
-	builder.rootEdge.Order = append(builder.rootEdge.Order, orderBy{
+	builder.rootEdge.Order = appendDQLizers(builder.rootEdge.Order, orderBy{
 		Direction: OrderDirectionDesc,
 		Predicate: predicate,
 	})
@@ -199,9 +201,7 @@ func (builder QueryBuilder) OrderDesc(predicate interface{}) QueryBuilder {
 // Example:
 //   dqlx.Query(...).Filter(dqlx.Eq{...}, dqlx.Gt{...})
 func (builder QueryBuilder) Filter(filters ...DQLizer) QueryBuilder {
-	for _, filter := range filters {
-		builder.rootEdge.Filters = append(builder.rootEdge.Filters, filter)
-	}
+	builder.rootEdge.Filters = appendDQLizers(builder.rootEdge.Filters, filters...)
 	return builder
 }
 
@@ -217,7 +217,7 @@ func (builder QueryBuilder) Paginate(pagination Cursor) QueryBuilder {
 // GroupBy adds a groupby directive.
 func (builder QueryBuilder) GroupBy(predicates ...string) QueryBuilder {
 	for _, field := range predicates {
-		builder.rootEdge.Group = append(builder.rootEdge.Group, GroupBy(field))
+		builder.rootEdge.Group = appendDQLizers(builder.rootEdge.Group, GroupBy(field))
 	}
 	return builder
 }
@@ -319,12 +319,20 @@ func (builder QueryBuilder) EdgeFromQuery(edge QueryBuilder) QueryBuilder {
 }
 
 // UnmarshalInto requests to unmarshal the result set into this specific
-// interface{}.
+// interface{}. When nothing is selected yet and the value is a struct,
+// or a slice of structs, the selection set is derived from its tags.
 //
 // Example:
 //   dqlx.Query(...).UnmarshalInto(&value)+
 func (builder QueryBuilder) UnmarshalInto(value interface{}) QueryBuilder {
 	builder.unmarshalInto = value
+
+	if builder.rootEdge.Node.Attributes == nil {
+		if _, ok := newStructSelection(value); ok {
+			builder = builder.Select(value)
+		}
+	}
+
 	return builder
 }
 
@@ -386,11 +394,82 @@ func (builder QueryBuilder) addEdgeFn(as string, query QueryBuilder, fn func(bui
 		edgeBuilder = edgeBuilder.As(as)
 	}
 
-	builder.childrenEdges[parentPath] = append(builder.childrenEdges[parentPath], edgeBuilder)
+	// the edges map is shared by every builder derived from the same parent,
+	// so it gets copied before being written
+	childrenEdges := cloneEdges(edgeBuilder.childrenEdges, false)
+	childrenEdges[parentPath] = append(childrenEdges[parentPath], edgeBuilder)
+
+	builder.childrenEdges = childrenEdges
+	builder.rootEdge.Node.Edges = childrenEdges
+
+	return builder
+}
+
+// Clone returns a deep copy of the query builder, nested edges and variables
+// included. Filters and the UnmarshalInto value are shared with the copy.
+//
+// Builders are already safe to derive from, Clone is useful when a builder
+// needs to be detached from every other builder sharing its history.
+//
+// Example:
+//   base := dqlx.QueryType("User").Select("name")
+//   admins := base.Clone().Filter(dqlx.Eq{"role": "admin"})
+func (builder QueryBuilder) Clone() QueryBuilder {
+	builder.rootEdge = builder.rootEdge.clone()
+	builder.childrenEdges = cloneEdges(builder.childrenEdges, true)
+	builder.rootEdge.Node.Edges = builder.childrenEdges
+
+	if builder.variables != nil {
+		variables := make([]QueryBuilder, len(builder.variables))
+		for index, variable := range builder.variables {
+			variables[index] = variable.Clone()
+		}
+		builder.variables = variables
+	}
+
+	if builder.scopes != nil {
+		builder.scopes = append([]string{}, builder.scopes...)
+	}
 
 	return builder
 }
 
+// cloneEdges copies the edges map together with its slices.
+// when deep is true the nested edges are cloned as well and attached to
+// the new map
+func cloneEdges(edges map[string][]QueryBuilder, deep bool) map[string][]QueryBuilder {
+	clonedEdges := make(map[string][]QueryBuilder, len(edges))
+
+	for parentPath, queries := range edges {
+		clonedQueries := make([]QueryBuilder, len(queries))
+		copy(clonedQueries, queries)
+
+		if deep {
+			for index, query := range clonedQueries {
+				query.rootEdge = query.rootEdge.clone()
+				query.rootEdge.Node.Edges = clonedEdges
+				query.childrenEdges = clonedEdges
+				clonedQueries[index] = query
+			}
+		}
+
+		clonedEdges[parentPath] = clonedQueries
+	}
+
+	return clonedEdges
+}
+
+// appendDQLizers appends to a copy of parts, this way builders derived
+// from the same parent never share the same underlying array
+func appendDQLizers(parts []DQLizer, values ...DQLizer) []DQLizer {
+	return append(parts[:len(parts):len(parts)], values...)
+}
+
+// appendQueries appends to a copy of queries
+func appendQueries(queries []QueryBuilder, values ...QueryBuilder) []QueryBuilder {
+	return append(queries[:len(queries):len(queries)], values...)
+}
+
 // IsEmptyQuery indicates if a given query is an empty generated query.
 func IsEmptyQuery(query string) bool {
 	return "query () {  {  } }" == query
@@ -0,0 +1,74 @@
+package dqlx_test
+
+import (
+	"fmt"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+)
+
+func deepQuery(depth int) dql.QueryBuilder {
+	query := dql.QueryType("User").
+		Filter(dql.Eq{"active": true}).
+		Paginate(dql.Cursor{First: 10}).
+		Select("uid name age")
+
+	path := ""
+
+	for level := 0; level < depth; level++ {
+		if path == "" {
+			path = "friends"
+		} else {
+			path = dql.EdgePath(path, "friends")
+		}
+
+		query = query.Edge(path,
+			dql.Select("uid name", dql.Count("posts")),
+			dql.Gt{"age": level},
+			dql.Cursor{First: 20, Offset: level},
+			dql.OrderAsc("name"),
+		)
+	}
+
+	return query
+}
+
+func wideQuery(width int) dql.QueryBuilder {
+	query := dql.QueryType("User").
+		Filter(dql.Eq{"active": true}).
+		Paginate(dql.Cursor{First: 10}).
+		Select("uid name age")
+
+	for index := 0; index < width; index++ {
+		query = query.Edge(fmt.Sprintf("edge_%d", index),
+			dql.Select("uid name", dql.Alias("total", dql.Count("posts"))),
+			dql.Or{dql.Eq{"name": fmt.Sprintf("name %d", index)}, dql.Ge{"age": index}},
+			dql.Cursor{First: 20},
+		)
+	}
+
+	return query
+}
+
+func benchmarkToDQL(b *testing.B, query dql.QueryBuilder) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := query.ToDQL(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToDQL_Deep(b *testing.B) {
+	benchmarkToDQL(b, deepQuery(50))
+}
+
+func BenchmarkToDQL_Wide(b *testing.B) {
+	benchmarkToDQL(b, wideQuery(300))
+}
+
+func BenchmarkToDQL_Small(b *testing.B) {
+	benchmarkToDQL(b, deepQuery(2))
+}
@@ -0,0 +1,317 @@
+package dqlx
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ExampleMatcher builds the filter of a single field of an example
+type ExampleMatcher func(predicate string, value interface{}) (DQLizer, error)
+
+// ExampleOptionFn used to modify options of FilterByExample
+type ExampleOptionFn func(example *exampleOptions)
+
+type exampleOptions struct {
+	matchers map[string]ExampleMatcher
+}
+
+// WithExampleMatcher uses the matcher for the fields mapped to the predicate,
+// it takes precedence over the matcher named in the struct tag
+//
+// Example:
+//   dqlx.FilterByExample(search, dqlx.WithExampleMatcher("name", dqlx.MatchAllOfTerms))
+func WithExampleMatcher(predicate string, matcher ExampleMatcher) ExampleOptionFn {
+	return func(example *exampleOptions) {
+		example.matchers[predicate] = matcher
+	}
+}
+
+var exampleMatchers = map[string]ExampleMatcher{
+	"eq":         MatchEq,
+	"gt":         MatchGt,
+	"ge":         MatchGe,
+	"lt":         MatchLt,
+	"le":         MatchLe,
+	"allofterms": MatchAllOfTerms,
+	"anyofterms": MatchAnyOfTerms,
+	"alloftext":  MatchAllOfText,
+	"anyoftext":  MatchAnyOfText,
+	"regexp":     MatchRegexp,
+	"glob":       MatchGlob,
+	"between":    MatchBetween,
+}
+
+// MatchEq matches fields with eq(predicate, value),
+// slices match any of their values
+func MatchEq(predicate string, value interface{}) (DQLizer, error) {
+	return Eq{predicate: value}, nil
+}
+
+// MatchGt matches fields with gt(predicate, value)
+func MatchGt(predicate string, value interface{}) (DQLizer, error) {
+	return Gt{predicate: value}, nil
+}
+
+// MatchGe matches fields with ge(predicate, value)
+func MatchGe(predicate string, value interface{}) (DQLizer, error) {
+	return Ge{predicate: value}, nil
+}
+
+// MatchLt matches fields with lt(predicate, value)
+func MatchLt(predicate string, value interface{}) (DQLizer, error) {
+	return Lt{predicate: value}, nil
+}
+
+// MatchLe matches fields with le(predicate, value)
+func MatchLe(predicate string, value interface{}) (DQLizer, error) {
+	return Le{predicate: value}, nil
+}
+
+// MatchAllOfTerms matches fields with allofterms(predicate, value)
+func MatchAllOfTerms(predicate string, value interface{}) (DQLizer, error) {
+	return AllOfTerms{predicate: value}, nil
+}
+
+// MatchAnyOfTerms matches fields with anyofterms(predicate, value)
+func MatchAnyOfTerms(predicate string, value interface{}) (DQLizer, error) {
+	return AnyOfTerms{predicate: value}, nil
+}
+
+// MatchAllOfText matches fields with alloftext(predicate, value)
+func MatchAllOfText(predicate string, value interface{}) (DQLizer, error) {
+	return AllOfText{predicate: value}, nil
+}
+
+// MatchAnyOfText matches fields with anyoftext(predicate, value)
+func MatchAnyOfText(predicate string, value interface{}) (DQLizer, error) {
+	return AnyOfText{predicate: value}, nil
+}
+
+// MatchRegexp matches fields with regexp(predicate, /value/),
+// the value is validated as a regular expression, see NewRegexp
+func MatchRegexp(predicate string, value interface{}) (DQLizer, error) {
+	pattern, ok := value.(string)
+
+	if !ok {
+		return nil, fmt.Errorf("regexp on '%s' expects a string, given %T", predicate, value)
+	}
+
+	return NewRegexp(predicate, pattern)
+}
+
+// MatchGlob matches fields with a glob pattern,
+// * matches any text and ? a single character
+func MatchGlob(predicate string, value interface{}) (DQLizer, error) {
+	pattern, ok := value.(string)
+
+	if !ok {
+		return nil, fmt.Errorf("glob on '%s' expects a string, given %T", predicate, value)
+	}
+
+	return NewRegexp(predicate, globToRegexp(pattern))
+}
+
+// MatchBetween matches fields with between(predicate, from, to),
+// the value must be a slice or array of two elements
+func MatchBetween(predicate string, value interface{}) (DQLizer, error) {
+	values, ok := value.([]interface{})
+
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("between on '%s' expects two values", predicate)
+	}
+
+	return Between(predicate, values[0], values[1]), nil
+}
+
+// FilterByExample builds an And filter out of the non-zero fields of a struct.
+// Fields are mapped to predicates using the dql tag, the json tag or the field name.
+// The tag may name the matcher to use (eq by default): gt, ge, lt, le,
+// allofterms, anyofterms, alloftext, anyoftext, regexp, glob, between.
+// Nil pointers are skipped, set pointers are matched even when the value is zero.
+//
+// Example:
+//   type UserSearch struct {
+//     Name   string  `dql:"name,allofterms"`
+//     MinAge int     `dql:"age,ge"`
+//     MaxAge int     `dql:"age,le"`
+//     Active *bool   `json:"active"`
+//   }
+//
+//   filter, err := dqlx.FilterByExample(UserSearch{Name: "alice", MinAge: 18})
+//   dqlx.QueryType("User").Filter(filter)
+func FilterByExample(example interface{}, options ...ExampleOptionFn) (And, error) {
+	exampleOptions := &exampleOptions{
+		matchers: map[string]ExampleMatcher{},
+	}
+
+	for _, option := range options {
+		option(exampleOptions)
+	}
+
+	value := reflect.ValueOf(example)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return And{}, nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FilterByExample expects a struct, given %T", example)
+	}
+
+	return exampleOptions.filters(value, And{})
+}
+
+func (options *exampleOptions) filters(value reflect.Value, filters And) (And, error) {
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		fieldValue := value.Field(i)
+
+		predicate, matcherName, skip := exampleTag(field)
+
+		if skip {
+			continue
+		}
+
+		isPointer := fieldValue.Kind() == reflect.Ptr
+
+		if isPointer {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct && !hasExampleTag(field) {
+			var err error
+			filters, err = options.filters(fieldValue, filters)
+
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !isPointer && fieldValue.IsZero() {
+			continue
+		}
+
+		matcher, ok := options.matchers[predicate]
+
+		if !ok {
+			matcher, ok = exampleMatchers[matcherName]
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("unknown matcher '%s' on field %s", matcherName, field.Name)
+		}
+
+		fieldInterface, err := exampleValue(fieldValue)
+
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		filter, err := matcher(predicate, fieldInterface)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func hasExampleTag(field reflect.StructField) bool {
+	return field.Tag.Get("dql") != "" || field.Tag.Get("json") != ""
+}
+
+// exampleTag returns the predicate and matcher of the field
+func exampleTag(field reflect.StructField) (predicate string, matcher string, skip bool) {
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", "", true
+	}
+
+	predicate = field.Name
+	matcher = "eq"
+
+	if tag, ok := field.Tag.Lookup("dql"); ok {
+		parts := strings.Split(tag, ",")
+
+		if parts[0] == "-" {
+			return "", "", true
+		}
+
+		if parts[0] != "" {
+			predicate = parts[0]
+		}
+
+		if len(parts) > 1 && parts[1] != "" {
+			matcher = strings.ToLower(parts[1])
+		}
+
+		return predicate, matcher, false
+	}
+
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		name := strings.Split(tag, ",")[0]
+
+		if name == "-" {
+			return "", "", true
+		}
+
+		if name != "" {
+			predicate = name
+		}
+	}
+
+	return predicate, matcher, false
+}
+
+// exampleValue converts the value into a type supported by goTypeToDQLType
+func exampleValue(value reflect.Value) (interface{}, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("value %d overflows int64", value.Uint())
+		}
+		return int64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, value.Len())
+
+		for i := range values {
+			item, err := exampleValue(value.Index(i))
+
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = item
+		}
+
+		return values, nil
+	case reflect.Struct:
+		if date, ok := value.Interface().(time.Time); ok {
+			return date, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", value.Type())
+}
@@ -0,0 +1,134 @@
+package dqlx_test
+
+import (
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+type paging struct {
+	Owner string `json:"owner"`
+}
+
+type userSearch struct {
+	paging
+	Name     string    `dql:"name,allofterms"`
+	Email    string    `json:"email,omitempty"`
+	MinAge   uint8     `dql:"age,ge"`
+	MaxAge   int       `dql:"age,le"`
+	Active   *bool     `json:"active"`
+	Status   []string  `dql:"status"`
+	Nickname string    `dql:"nickname,glob"`
+	Since    time.Time `dql:"created_at,gt"`
+	Ignored  string    `dql:"-"`
+	internal string
+}
+
+func Test_Filter_By_Example(t *testing.T) {
+	active := false
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	filter, err := dql.FilterByExample(&userSearch{
+		paging:   paging{Owner: "0x1"},
+		Name:     "alice",
+		MinAge:   18,
+		Active:   &active,
+		Status:   []string{"a", "b"},
+		Nickname: "al*",
+		Since:    since,
+		Ignored:  "ignored",
+		internal: "ignored",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, dql.And{
+		dql.Eq{"owner": "0x1"},
+		dql.AllOfTerms{"name": "alice"},
+		dql.Ge{"age": int64(18)},
+		dql.Eq{"active": false},
+		dql.Eq{"status": []interface{}{"a", "b"}},
+		mustRegexp("nickname", "/^al.*$/"),
+		dql.Gt{"created_at": since},
+	}, filter)
+}
+
+func Test_Filter_By_Example_Query(t *testing.T) {
+	filter, err := dql.FilterByExample(
+		userSearch{Name: "alice", MaxAge: 65},
+		dql.WithExampleMatcher("name", dql.MatchRegexp),
+	)
+	require.NoError(t, err)
+
+	query, variables, err := dql.QueryType("User").Filter(filter).ToDQL()
+	require.NoError(t, err)
+
+	expected := dql.Minify(`
+		query Rootquery($0:string, $1:int) {
+			<rootQuery>(func: type(<User>)) @filter((regexp(<name>,$0) AND le(<age>,$1))) {  }
+		}
+	`)
+
+	require.Equal(t, expected, dql.Minify(query))
+	require.Equal(t, map[string]string{"$0": "/alice/", "$1": "65"}, variables)
+}
+
+func Test_Filter_By_Example_Regexp_Injection(t *testing.T) {
+	cases := map[string]struct {
+		search   userSearch
+		expected string
+	}{
+		"delimited": {
+			search:   userSearch{Name: "/a/) OR has(<password>"},
+			expected: "regexp on 'name': unsupported flags ') OR has(<password>'",
+		},
+		"escaped delimiter": {
+			search:   userSearch{Name: `x\/) OR has(password) OR regexp(x, /`},
+			expected: "regexp on 'name': error parsing regexp: unexpected ): `x\\/) OR has(password) OR regexp(x, /`",
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := dql.FilterByExample(testCase.search, dql.WithExampleMatcher("name", dql.MatchRegexp))
+			require.EqualError(t, err, testCase.expected)
+		})
+	}
+
+	// globs match the value literally, the pattern is bound as a variable
+	filter, err := dql.FilterByExample(userSearch{Nickname: "a/) OR has(<password>"})
+	require.NoError(t, err)
+
+	query, variables, err := dql.QueryType("User").Filter(filter).ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, "@filter((regexp(<nickname>,$0)))")
+	require.NotContains(t, query, "password")
+	require.Equal(t, map[string]string{"$0": `/^a\/\) OR has\(<password>$/`}, variables)
+}
+
+func Test_Filter_By_Example_Empty(t *testing.T) {
+	filter, err := dql.FilterByExample(userSearch{})
+	require.NoError(t, err)
+	require.Empty(t, filter)
+}
+
+func Test_Filter_By_Example_Errors(t *testing.T) {
+	_, err := dql.FilterByExample("alice")
+	require.EqualError(t, err, "FilterByExample expects a struct, given string")
+
+	_, err = dql.FilterByExample(struct {
+		Name string `dql:"name,unknown"`
+	}{Name: "alice"})
+	require.EqualError(t, err, "unknown matcher 'unknown' on field Name")
+
+	_, err = dql.FilterByExample(struct {
+		Tags map[string]string `dql:"tags"`
+	}{Tags: map[string]string{"a": "b"}})
+	require.EqualError(t, err, "field Tags: unsupported type map[string]string")
+
+	_, err = dql.FilterByExample(struct {
+		Age int `dql:"age,between"`
+	}{Age: 5})
+	require.EqualError(t, err, "between on 'age' expects two values")
+}
@@ -0,0 +1,458 @@
+package dqlx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GetEdge returns the edge at the given path.
+//
+// Example:
+//   edge, ok := dqlx.Query(...).GetEdge("friends->posts")
+func (builder QueryBuilder) GetEdge(fullPath string) (QueryBuilder, bool) {
+	parentPath, index, ok := builder.findEdge(fullPath)
+
+	if !ok {
+		return QueryBuilder{}, false
+	}
+
+	edgeBuilder := builder.childrenEdges[parentPath][index]
+	edgeBuilder.childrenEdges = builder.childrenEdges
+	edgeBuilder.rootEdge.Node.Edges = builder.childrenEdges
+
+	return edgeBuilder, true
+}
+
+// HasEdge determines if an edge exists at the given path
+//
+// Example:
+//   dqlx.Query(...).HasEdge("friends->posts")
+func (builder QueryBuilder) HasEdge(fullPath string) bool {
+	_, _, ok := builder.findEdge(fullPath)
+	return ok
+}
+
+// EditEdge replaces the edge at the given path with the result of the callback.
+// If the edge doesn't exist the builder is returned unchanged.
+//
+// Example:
+//   dqlx.Query(...).EditEdge("friends->posts", func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//     return builder.ClearFilters().Filter(dqlx.Eq{"published": true})
+//   })
+func (builder QueryBuilder) EditEdge(fullPath string, fn func(builder QueryBuilder) QueryBuilder) QueryBuilder {
+	edgeBuilder, ok := builder.GetEdge(fullPath)
+
+	if !ok {
+		return builder
+	}
+
+	parentPath, index, _ := builder.findEdge(fullPath)
+
+	edgeBuilder = fn(edgeBuilder)
+
+	childrenEdges := cloneEdges(edgeBuilder.childrenEdges, false)
+
+	if index < len(childrenEdges[parentPath]) {
+		childrenEdges[parentPath][index] = edgeBuilder
+	} else {
+		childrenEdges[parentPath] = append(childrenEdges[parentPath], edgeBuilder)
+	}
+
+	builder.childrenEdges = childrenEdges
+	builder.rootEdge.Node.Edges = childrenEdges
+
+	return builder
+}
+
+// RemoveEdge removes the edge at the given path together with its nested edges.
+//
+// Example:
+//   dqlx.Query(...).RemoveEdge("friends->posts")
+func (builder QueryBuilder) RemoveEdge(fullPath string) QueryBuilder {
+	parentPath, index, ok := builder.findEdge(fullPath)
+
+	if !ok {
+		return builder
+	}
+
+	return builder.removeEdge(parentPath, index)
+}
+
+// RemoveEdgeAs removes the aliased edge at the given path.
+// Nested edges are removed unless a sibling with the same path still uses them
+//
+// Example:
+//   dqlx.Query(...).EdgeAs("A", "friends").EdgeAs("B", "friends").RemoveEdgeAs("B", "friends")
+func (builder QueryBuilder) RemoveEdgeAs(as string, fullPath string) QueryBuilder {
+	parentPath, index, ok := builder.findEdgeAs(as, fullPath)
+
+	if !ok {
+		return builder
+	}
+
+	return builder.removeEdge(parentPath, index)
+}
+
+func (builder QueryBuilder) removeEdge(parentPath string, index int) QueryBuilder {
+	removedEdge := builder.childrenEdges[parentPath][index]
+	childrenEdges := cloneEdges(builder.childrenEdges, false)
+
+	siblings := childrenEdges[parentPath]
+	childrenEdges[parentPath] = append(siblings[:index], siblings[index+1:]...)
+
+	if len(childrenEdges[parentPath]) == 0 {
+		delete(childrenEdges, parentPath)
+	}
+
+	// aliased siblings of the same path render the same nested edges
+	if findSibling(childrenEdges[parentPath], removedEdge.rootEdge, false) != -1 {
+		builder.childrenEdges = childrenEdges
+		builder.rootEdge.Node.Edges = childrenEdges
+		return builder
+	}
+
+	for path := range childrenEdges {
+		if isNestedPath(path, removedEdge.rootEdge.Name) {
+			delete(childrenEdges, path)
+		}
+	}
+
+	builder.childrenEdges = childrenEdges
+	builder.rootEdge.Node.Edges = childrenEdges
+
+	return builder
+}
+
+// GetFilters returns the filters of the query
+func (builder QueryBuilder) GetFilters() []DQLizer {
+	return cloneDQLizers(builder.rootEdge.Filters)
+}
+
+// ClearFilters removes all the filters of the query
+//
+// Example:
+//   dqlx.Query(...).ClearFilters().Filter(dqlx.Eq{...})
+func (builder QueryBuilder) ClearFilters() QueryBuilder {
+	builder.rootEdge.Filters = []DQLizer{}
+	return builder
+}
+
+// GetSelection returns the selected predicates of the query
+func (builder QueryBuilder) GetSelection() []interface{} {
+	attributes, ok := builder.rootEdge.Node.Attributes.(nodeAttributes)
+
+	if !ok {
+		return nil
+	}
+
+	return append([]interface{}{}, attributes.predicates...)
+}
+
+// ClearSelection removes the selected predicates of the query.
+// Nested edges are kept
+func (builder QueryBuilder) ClearSelection() QueryBuilder {
+	builder.rootEdge.Node = node{
+		ParentName: builder.rootEdge.Name,
+		Edges:      builder.childrenEdges,
+	}
+	return builder
+}
+
+// GetOrder returns the order clauses of the query
+func (builder QueryBuilder) GetOrder() []DQLizer {
+	return cloneDQLizers(builder.rootEdge.Order)
+}
+
+// ClearOrder removes the order clauses of the query
+func (builder QueryBuilder) ClearOrder() QueryBuilder {
+	builder.rootEdge.Order = nil
+	return builder
+}
+
+// GetPagination returns the pagination of the query
+func (builder QueryBuilder) GetPagination() Cursor {
+	return builder.rootEdge.Pagination
+}
+
+// ClearPagination removes the pagination of the query
+func (builder QueryBuilder) ClearPagination() QueryBuilder {
+	builder.rootEdge.Pagination = Cursor{}
+	return builder
+}
+
+// ClearFacets removes the facets of the query
+func (builder QueryBuilder) ClearFacets() QueryBuilder {
+	builder.rootEdge.Facets = nil
+	return builder
+}
+
+// ClearGroupBy removes the groupby directive of the query
+func (builder QueryBuilder) ClearGroupBy() QueryBuilder {
+	builder.rootEdge.Group = nil
+	return builder
+}
+
+// ClearCascade removes the cascade directive of the query
+func (builder QueryBuilder) ClearCascade() QueryBuilder {
+	builder.rootEdge.Cascade = nil
+	return builder
+}
+
+// Merge combines the selections and edges of two builders targeting the same
+// root into one. Filters, order, pagination, facets, groupby and cascade are
+// taken from the builder defining them, an error is returned when both
+// builders define them with different values.
+//
+// Example:
+//   merged, err := dqlx.QueryType("User").Select("name").Merge(dqlx.QueryType("User").Select("email"))
+func (builder QueryBuilder) Merge(other QueryBuilder) (QueryBuilder, error) {
+	if builder.rootEdge.Name != other.rootEdge.Name {
+		return builder, fmt.Errorf("cannot merge query '%s' with query '%s'", builder.rootEdge.Name, other.rootEdge.Name)
+	}
+
+	if builder.rootEdge.IsVariable != other.rootEdge.IsVariable {
+		return builder, fmt.Errorf("cannot merge a variable and a query block '%s'", builder.rootEdge.Name)
+	}
+
+	sameRoot, err := isSameDQLizer(builder.rootEdge.RootFilter, other.rootEdge.RootFilter)
+
+	if err != nil {
+		return builder, err
+	}
+
+	if !sameRoot {
+		return builder, fmt.Errorf("cannot merge query '%s' with a different root function", builder.rootEdge.Name)
+	}
+
+	if builder.rootEdge, err = mergeEdges(builder.rootEdge, other.rootEdge); err != nil {
+		return builder, err
+	}
+
+	childrenEdges := cloneEdges(builder.childrenEdges, false)
+
+	for parentPath, otherEdges := range other.childrenEdges {
+		for _, otherEdge := range otherEdges {
+			index := findSibling(childrenEdges[parentPath], otherEdge.rootEdge, true)
+
+			if index == -1 {
+				childrenEdges[parentPath] = append(childrenEdges[parentPath], otherEdge)
+				continue
+			}
+
+			mergedEdge := childrenEdges[parentPath][index]
+
+			if mergedEdge.rootEdge, err = mergeEdges(mergedEdge.rootEdge, otherEdge.rootEdge); err != nil {
+				return builder, err
+			}
+
+			childrenEdges[parentPath][index] = mergedEdge
+		}
+	}
+
+	builder.childrenEdges = childrenEdges
+	builder.rootEdge.Node.Edges = childrenEdges
+	builder.variables = appendQueries(builder.variables, other.variables...)
+
+	return builder, nil
+}
+
+func (builder QueryBuilder) findEdge(fullPath string) (parentPath string, index int, ok bool) {
+	return builder.findEdgeMatching(fullPath, func(edge edge) bool {
+		return true
+	})
+}
+
+func (builder QueryBuilder) findEdgeAs(as string, fullPath string) (parentPath string, index int, ok bool) {
+	return builder.findEdgeMatching(fullPath, func(edge edge) bool {
+		return edge.Alias == as
+	})
+}
+
+func (builder QueryBuilder) findEdgeMatching(fullPath string, match func(edge edge) bool) (parentPath string, index int, ok bool) {
+	edgePathParts := ParseEdge(fullPath)
+
+	if fullPath == "" || len(edgePathParts) == 0 {
+		return "", -1, false
+	}
+
+	if len(edgePathParts) == 1 {
+		parentPath = builder.rootEdge.Name
+	} else {
+		parentPath = EdgePath(edgePathParts[0 : len(edgePathParts)-1]...)
+	}
+
+	edgeName := edgePathParts[len(edgePathParts)-1]
+
+	for index, edgeBuilder := range builder.childrenEdges[parentPath] {
+		if edgeBuilder.rootEdge.RelativeName() == edgeName && match(edgeBuilder.rootEdge) {
+			return parentPath, index, true
+		}
+	}
+
+	return "", -1, false
+}
+
+// findSibling returns the index of the sibling with the same path,
+// and the same alias when sameAlias is true
+func findSibling(siblings []QueryBuilder, edge edge, sameAlias bool) int {
+	for index, sibling := range siblings {
+		if sibling.rootEdge.RelativeName() != edge.RelativeName() {
+			continue
+		}
+
+		if !sameAlias || sibling.rootEdge.Alias == edge.Alias {
+			return index
+		}
+	}
+	return -1
+}
+
+func isNestedPath(path string, parentPath string) bool {
+	return path == parentPath || strings.HasPrefix(path, parentPath+symbolEdgeTraversal)
+}
+
+func mergeEdges(target edge, source edge) (edge, error) {
+	var err error
+
+	if target.Filters, err = mergeDQLizers(target.Name, "filters", target.Filters, source.Filters); err != nil {
+		return target, err
+	}
+
+	if target.Order, err = mergeDQLizers(target.Name, "order", target.Order, source.Order); err != nil {
+		return target, err
+	}
+
+	if target.Facets, err = mergeDQLizers(target.Name, "facets", target.Facets, source.Facets); err != nil {
+		return target, err
+	}
+
+	if target.Group, err = mergeDQLizers(target.Name, "groupby", target.Group, source.Group); err != nil {
+		return target, err
+	}
+
+	switch {
+	case !target.Pagination.WantsPagination():
+		target.Pagination = source.Pagination
+	case source.Pagination.WantsPagination() && source.Pagination != target.Pagination:
+		return target, fmt.Errorf("cannot merge '%s': conflicting pagination", target.Name)
+	}
+
+	cascade, err := mergeDQLizers(target.Name, "cascade", []DQLizer{target.Cascade}, []DQLizer{source.Cascade})
+
+	if err != nil {
+		return target, err
+	}
+
+	target.Cascade = cascade[0]
+
+	predicates := mergeSelections(target.Node.Attributes, source.Node.Attributes)
+
+	if predicates != nil {
+		target.Node.Attributes = nodeAttributes{predicates}
+		target.Node.HasParentAttributes = len(predicates) > 0
+	}
+
+	return target, nil
+}
+
+// mergeDQLizers returns the parts defined by either side,
+// parts defined by both sides must be the same
+func mergeDQLizers(name string, kind string, target []DQLizer, source []DQLizer) ([]DQLizer, error) {
+	if isEmptyParts(source) {
+		return target, nil
+	}
+
+	if isEmptyParts(target) {
+		return source, nil
+	}
+
+	same := len(target) == len(source)
+
+	for index := 0; same && index < len(target); index++ {
+		var err error
+
+		if same, err = isSameDQLizer(target[index], source[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !same {
+		return nil, fmt.Errorf("cannot merge '%s': conflicting %s", name, kind)
+	}
+
+	return target, nil
+}
+
+func isEmptyParts(parts []DQLizer) bool {
+	for _, part := range parts {
+		if part != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeSelections(target DQLizer, source DQLizer) []interface{} {
+	if target == nil && source == nil {
+		return nil
+	}
+
+	var predicates []interface{}
+
+	for _, selection := range []DQLizer{target, source} {
+		attributes, ok := selection.(nodeAttributes)
+
+		if !ok {
+			continue
+		}
+
+		for _, predicate := range attributes.predicates {
+			if raw, ok := predicate.(string); ok {
+				for _, line := range strings.Split(raw, "\n") {
+					line = Minify(line)
+
+					if line != "" && !containsPredicate(predicates, line) {
+						predicates = append(predicates, line)
+					}
+				}
+				continue
+			}
+
+			if !containsPredicate(predicates, predicate) {
+				predicates = append(predicates, predicate)
+			}
+		}
+	}
+
+	return predicates
+}
+
+func containsPredicate(predicates []interface{}, predicate interface{}) bool {
+	for _, selected := range predicates {
+		if reflect.DeepEqual(selected, predicate) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSameDQLizer(a DQLizer, b DQLizer) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+
+	queryA, argsA, err := a.ToDQL()
+
+	if err != nil {
+		return false, err
+	}
+
+	queryB, argsB, err := b.ToDQL()
+
+	if err != nil {
+		return false, err
+	}
+
+	return queryA == queryB && reflect.DeepEqual(argsA, argsB), nil
+}
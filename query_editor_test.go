@@ -0,0 +1,200 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func baseEditorQuery() dql.QueryBuilder {
+	return dql.QueryType("User").
+		Select("uid", "name").
+		Filter(dql.Eq{"active": true}).
+		Paginate(dql.Cursor{First: 10}).
+		Edge("friends", dql.Select("name"), dql.Cursor{First: 5}).
+		Edge("friends->posts", dql.Select("title"), dql.Eq{"published": true}).
+		Edge("friends->posts->comments", dql.Select("text"))
+}
+
+func Test_Query_Editor_Edges(t *testing.T) {
+	base := baseEditorQuery()
+
+	edge, ok := base.GetEdge("friends->posts")
+	require.True(t, ok)
+	require.Len(t, edge.GetFilters(), 1)
+	require.Equal(t, []interface{}{"title"}, edge.GetSelection())
+
+	require.True(t, base.HasEdge("friends->posts->comments"))
+	require.False(t, base.HasEdge("friends->groups"))
+
+	edited := base.
+		EditEdge("friends->posts", func(builder dql.QueryBuilder) dql.QueryBuilder {
+			return builder.
+				ClearFilters().
+				ClearSelection().
+				Select("uid").
+				Filter(dql.Has("title")).
+				Edge("friends->posts->likes", dql.Select("uid"))
+		}).
+		EditEdge("friends", func(builder dql.QueryBuilder) dql.QueryBuilder {
+			return builder.ClearPagination()
+		})
+
+	query, variables, err := edited.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"$0": "10", "$1": "true"}, variables)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:int, $1:bool) {
+			<rootQuery>(func: type(<User>),first:$0) @filter(eq(<active>,$1)) {
+				<uid>
+				<name>
+				<friends> {
+					<name>
+					<posts> @filter(has(<title>)) {
+						<uid>
+						<comments> { <text> }
+						<likes> { <uid> }
+					}
+				}
+			}
+		}
+	`), query)
+
+	removed := base.RemoveEdge("friends->posts").ClearFilters().ClearPagination()
+
+	query, variables, err = removed.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"$0": "5"}, variables)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:int) {
+			<rootQuery>(func: type(<User>)) {
+				<uid>
+				<name>
+				<friends>(first:$0) { <name> }
+			}
+		}
+	`), query)
+
+	// the base query is left untouched
+	query, _, err = base.ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, "<comments> { <text> }")
+	require.Contains(t, query, "@filter(eq(<published>,$3))")
+}
+
+func Test_Query_Merge(t *testing.T) {
+	profile := dql.QueryType("User").
+		Select("uid", "name").
+		Filter(dql.Eq{"active": true}).
+		Edge("friends", dql.Select("name"))
+
+	contacts := dql.QueryType("User").
+		Select(`
+			name
+			email
+		`).
+		OrderAsc("name").
+		Edge("friends", dql.Select("email")).
+		Edge("groups", dql.Select("name"))
+
+	merged, err := profile.Merge(contacts)
+	require.NoError(t, err)
+
+	query, _, err := merged.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:bool) {
+			<rootQuery>(func: type(<User>),orderasc:<name>) @filter(eq(<active>,$0)) {
+				<uid>
+				<name>
+				<email>
+				<friends> { <name> <email> }
+				<groups> { <name> }
+			}
+		}
+	`), query)
+
+	_, err = profile.Merge(dql.QueryType("Post"))
+	require.Error(t, err)
+
+	_, err = profile.Merge(dql.QueryEdge("other", dql.TypeFn("User")))
+	require.Error(t, err)
+}
+
+func Test_Query_Merge_Conflicts(t *testing.T) {
+	base := dql.QueryType("User").
+		Select("name").
+		Filter(dql.Eq{"active": true}).
+		OrderAsc("name").
+		Paginate(dql.Cursor{First: 10}).
+		Edge("friends", dql.Select("name"), dql.Eq{"active": true})
+
+	// identical values are merged
+	merged, err := base.Merge(base.Select("email"))
+	require.NoError(t, err)
+
+	query, _, err := merged.ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, "<name> <email>")
+
+	cases := map[string]struct {
+		other    dql.QueryBuilder
+		expected string
+	}{
+		"filters": {
+			other:    dql.QueryType("User").Filter(dql.Eq{"active": false}),
+			expected: "cannot merge 'rootQuery': conflicting filters",
+		},
+		"order": {
+			other:    dql.QueryType("User").OrderDesc("name"),
+			expected: "cannot merge 'rootQuery': conflicting order",
+		},
+		"pagination": {
+			other:    dql.QueryType("User").Paginate(dql.Cursor{First: 20}),
+			expected: "cannot merge 'rootQuery': conflicting pagination",
+		},
+		"edge filters": {
+			other:    dql.QueryType("User").Edge("friends", dql.Has("email")),
+			expected: "cannot merge 'friends': conflicting filters",
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := base.Merge(testCase.other)
+			require.EqualError(t, err, testCase.expected)
+		})
+	}
+}
+
+func Test_Query_Remove_Aliased_Edge(t *testing.T) {
+	query := dql.QueryType("User").
+		EdgeAs("A", "friends", dql.Select("name")).
+		EdgeAs("B", "friends", dql.Select("name")).
+		Edge("friends->posts", dql.Select("title"))
+
+	removed := query.RemoveEdgeAs("B", "friends")
+
+	result, _, err := removed.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>)) {
+				A as <friends> { <name> <posts> { <title> } }
+			}
+		}
+	`), result)
+
+	// removing the first sibling keeps the nested edges of the other one
+	result, _, err = query.RemoveEdge("friends").ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, result, "B as <friends> { <name> <posts> { <title> } }")
+
+	// removing the last sibling drops the nested edges
+	removed = removed.RemoveEdgeAs("A", "friends")
+	require.False(t, removed.HasEdge("friends->posts"))
+
+	// unknown aliases leave the query unchanged
+	require.Equal(t, query, query.RemoveEdgeAs("C", "friends"))
+}
@@ -280,6 +280,24 @@ func (suite *QueryIntegrationTest) TestFundamentals() {
 			"first_name_alias",
 		})
 	})
+
+	suite.Run("Typed", func() {
+		type film struct {
+			UID  string `json:"uid"`
+			Name string `json:"name@en"`
+		}
+
+		films, err := dqlx.QueryAll[film](ctx, suite.db.Query(dqlx.EqFn("name@en", "Blade Runner")))
+
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), films, 1)
+		require.Equal(suite.T(), "Blade Runner", films[0].Name)
+
+		single, err := dqlx.QueryOne[film](ctx, suite.db.Query(dqlx.UIDFn(films[0].UID)))
+
+		require.NoError(suite.T(), err)
+		require.Equal(suite.T(), films[0], single)
+	})
 }
 
 func (suite *QueryIntegrationTest) recordsContainsProperties(slice interface{}, properties []string) {
@@ -0,0 +1,1152 @@
+package dqlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const queryDocumentVersion = 1
+
+var (
+	countExpressionPattern     = regexp.MustCompile(`^count\(<([^<>]+)>\)$`)
+	aggregateExpressionPattern = regexp.MustCompile(`^(sum|avg|min|max)\(val\(<([^<>]+)>\)\)$`)
+	documentFuncPattern        = regexp.MustCompile(`^[a-z_]+$`)
+)
+
+var aggregateFuncs = map[string]func(predicate string) RawExpression{
+	"sum": Sum,
+	"avg": Avg,
+	"min": Min,
+	"max": Max,
+}
+
+type queryDocument struct {
+	Version   int             `json:"version,omitempty"`
+	Query     edgeDocument    `json:"query"`
+	Variables []queryDocument `json:"variables,omitempty"`
+	Scopes    []string        `json:"scopes,omitempty"`
+}
+
+type edgeDocument struct {
+	Name       string                `json:"name"`
+	Alias      string                `json:"alias,omitempty"`
+	IsVariable bool                  `json:"variable,omitempty"`
+	RootFilter *filterDocument       `json:"func,omitempty"`
+	Filters    []filterDocument      `json:"filters,omitempty"`
+	Pagination *paginationDocument   `json:"pagination,omitempty"`
+	Order      []orderDocument       `json:"order,omitempty"`
+	GroupBy    []string              `json:"groupBy,omitempty"`
+	Facets     [][]selectionDocument `json:"facets,omitempty"`
+	Cascade    *cascadeDocument      `json:"cascade,omitempty"`
+	Select     []selectionDocument   `json:"select,omitempty"`
+	Edges      []edgeDocument        `json:"edges,omitempty"`
+}
+
+type paginationDocument struct {
+	First  int    `json:"first,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type cascadeDocument struct {
+	Fields []string `json:"fields,omitempty"`
+}
+
+// filterDocument represents a filter, either a connective (operator and filters),
+// a function applied to predicates (func and terms), a function applied to
+// a value (func and value), a function with arguments (func, predicate and args)
+// or a raw DQL statement (dql and args).
+// has, type, val, count and the aggregations are stored as func and predicate
+type filterDocument struct {
+	Operator  string           `json:"operator,omitempty"`
+	Filters   []filterDocument `json:"filters,omitempty"`
+	Func      string           `json:"func,omitempty"`
+	Terms     []termDocument   `json:"terms,omitempty"`
+	Predicate string           `json:"predicate,omitempty"`
+	Value     *valueDocument   `json:"value,omitempty"`
+	Args      []valueDocument  `json:"args,omitempty"`
+	DQL       string           `json:"dql,omitempty"`
+}
+
+type termDocument struct {
+	Predicate string        `json:"predicate"`
+	Value     valueDocument `json:"value"`
+}
+
+type orderDocument struct {
+	Direction OrderDirection  `json:"direction,omitempty"`
+	Predicate string          `json:"predicate,omitempty"`
+	Value     *valueDocument  `json:"value,omitempty"`
+	Filter    *filterDocument `json:"filter,omitempty"`
+}
+
+type selectionDocument struct {
+	Field    string             `json:"field,omitempty"`
+	Alias    string             `json:"alias,omitempty"`
+	Variable string             `json:"variable,omitempty"`
+	Of       *selectionDocument `json:"of,omitempty"`
+	Filter   *filterDocument    `json:"filter,omitempty"`
+}
+
+// valueDocument represents a value together with its Go type,
+// so that values keep their DQL type once decoded
+type valueDocument struct {
+	Type   string          `json:"type"`
+	Value  json.RawMessage `json:"value,omitempty"`
+	Items  []valueDocument `json:"items,omitempty"`
+	Filter *filterDocument `json:"filter,omitempty"`
+}
+
+// statement represents a DQL statement restored from its query and arguments
+type statement struct {
+	query string
+	args  []interface{}
+}
+
+// ToDQL returns the DQL statement
+func (statement statement) ToDQL() (query string, args []interface{}, err error) {
+	return statement.query, statement.args, nil
+}
+
+// MarshalJSON returns a stable JSON representation of the query,
+// nested edges and variables included.
+// Values keep their Go type, the UnmarshalInto value and the client are not serialized
+//
+// Example:
+//   data, err := json.Marshal(dqlx.Query(...))
+func (builder QueryBuilder) MarshalJSON() ([]byte, error) {
+	document, err := builder.toDocument()
+
+	if err != nil {
+		return nil, err
+	}
+
+	document.Version = queryDocumentVersion
+	return json.Marshal(document)
+}
+
+// UnmarshalJSON restores a query from its JSON representation,
+// raw DQL statements are refused. Use UnmarshalQuery to accept them
+//
+// Example:
+//   var query dqlx.QueryBuilder
+//   err := json.Unmarshal(data, &query)
+func (builder *QueryBuilder) UnmarshalJSON(data []byte) error {
+	query, err := NewQueryDecoder().Decode(data)
+
+	if err != nil {
+		return err
+	}
+
+	*builder = query
+	return nil
+}
+
+// QueryDecoder restores queries from their JSON representation.
+// Documents may come from untrusted storage: names are validated
+// against the strict mode grammar and raw DQL statements are refused by default
+type QueryDecoder struct {
+	allowRawDQL bool
+}
+
+// QueryDecoderOptionFn used to modify options of the query decoder
+type QueryDecoderOptionFn func(decoder *QueryDecoder)
+
+// WithRawDQL accepts the raw DQL statements of the documents, such as dqlx.Expr.
+// They are inserted in the query unchanged: only enable it for trusted documents
+func WithRawDQL(allowed bool) QueryDecoderOptionFn {
+	return func(decoder *QueryDecoder) {
+		decoder.allowRawDQL = allowed
+	}
+}
+
+// NewQueryDecoder creates a QueryDecoder
+func NewQueryDecoder(options ...QueryDecoderOptionFn) QueryDecoder {
+	decoder := QueryDecoder{}
+
+	for _, option := range options {
+		option(&decoder)
+	}
+
+	return decoder
+}
+
+// UnmarshalQuery restores a query from its JSON representation
+//
+// Example:
+//   query, err := dqlx.UnmarshalQuery(data, dqlx.WithRawDQL(true))
+func UnmarshalQuery(data []byte, options ...QueryDecoderOptionFn) (QueryBuilder, error) {
+	return NewQueryDecoder(options...).Decode(data)
+}
+
+// Decode restores a query from its JSON representation
+func (decoder QueryDecoder) Decode(data []byte) (QueryBuilder, error) {
+	document := queryDocument{}
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return QueryBuilder{}, err
+	}
+
+	if document.Version != queryDocumentVersion {
+		return QueryBuilder{}, fmt.Errorf("unsupported query document version %d", document.Version)
+	}
+
+	query, err := decoder.queryFromDocument(document)
+
+	if err != nil {
+		return QueryBuilder{}, err
+	}
+
+	if err := ValidateNames(query); err != nil {
+		return QueryBuilder{}, err
+	}
+
+	return query, nil
+}
+
+// rawDQL returns a raw DQL statement if allowed by the decoder
+func (decoder QueryDecoder) rawDQL(statement string) (RawExpression, error) {
+	if !decoder.allowRawDQL {
+		return RawExpression{}, fmt.Errorf("raw DQL '%s' is not allowed in query documents", statement)
+	}
+
+	return Expr(statement), nil
+}
+
+func (builder QueryBuilder) toDocument() (queryDocument, error) {
+	rootEdge := builder.rootEdge
+	rootEdge.Node.Edges = builder.childrenEdges
+
+	edgeDoc, err := edgeToDocument(rootEdge)
+
+	if err != nil {
+		return queryDocument{}, err
+	}
+
+	document := queryDocument{
+		Query:  edgeDoc,
+		Scopes: builder.scopes,
+	}
+
+	for _, variable := range builder.variables {
+		variableDoc, err := variable.toDocument()
+
+		if err != nil {
+			return queryDocument{}, err
+		}
+
+		document.Variables = append(document.Variables, variableDoc)
+	}
+
+	return document, nil
+}
+
+func edgeToDocument(edge edge) (edgeDocument, error) {
+	document := edgeDocument{
+		Name:       edge.RelativeName(),
+		Alias:      edge.Alias,
+		IsVariable: edge.IsRoot && edge.IsVariable,
+	}
+
+	if edge.IsRoot {
+		document.Name = edge.Name
+	}
+
+	var err error
+
+	if edge.RootFilter != nil {
+		rootFilter, err := filterToDocument(edge.RootFilter)
+
+		if err != nil {
+			return document, err
+		}
+
+		document.RootFilter = &rootFilter
+	}
+
+	if document.Filters, err = filtersToDocuments(edge.Filters); err != nil {
+		return document, err
+	}
+
+	if edge.Pagination.WantsPagination() {
+		document.Pagination = &paginationDocument{
+			First:  edge.Pagination.First,
+			Offset: edge.Pagination.Offset,
+			After:  edge.Pagination.After,
+		}
+	}
+
+	for _, order := range edge.Order {
+		orderDoc, err := orderToDocument(order)
+
+		if err != nil {
+			return document, err
+		}
+
+		document.Order = append(document.Order, orderDoc)
+	}
+
+	for _, groupBy := range edge.Group {
+		cast, ok := groupBy.(group)
+
+		if !ok {
+			return document, fmt.Errorf("cannot serialize groupby of type %T", groupBy)
+		}
+
+		document.GroupBy = append(document.GroupBy, cast.Predicate)
+	}
+
+	for _, facets := range edge.Facets {
+		cast, ok := facets.(facetExpr)
+
+		if !ok {
+			return document, fmt.Errorf("cannot serialize facets of type %T", facets)
+		}
+
+		facetsDoc, err := selectionsToDocuments(cast.Predicates)
+
+		if err != nil {
+			return document, err
+		}
+
+		if facetsDoc == nil {
+			facetsDoc = []selectionDocument{}
+		}
+
+		document.Facets = append(document.Facets, facetsDoc)
+	}
+
+	if edge.Cascade != nil {
+		cast, ok := edge.Cascade.(cascadeExpr)
+
+		if !ok {
+			return document, fmt.Errorf("cannot serialize cascade of type %T", edge.Cascade)
+		}
+
+		document.Cascade = &cascadeDocument{Fields: cast.fields}
+	}
+
+	if attributes, ok := edge.Node.Attributes.(nodeAttributes); ok {
+		if document.Select, err = selectionsToDocuments(attributes.predicates); err != nil {
+			return document, err
+		}
+	}
+
+	for _, nestedEdge := range edge.Node.Edges[edge.Node.ParentName] {
+		nestedRoot := nestedEdge.rootEdge
+		nestedRoot.Node.Edges = edge.Node.Edges
+
+		nestedDoc, err := edgeToDocument(nestedRoot)
+
+		if err != nil {
+			return document, err
+		}
+
+		document.Edges = append(document.Edges, nestedDoc)
+	}
+
+	return document, nil
+}
+
+func filtersToDocuments(filters []DQLizer) ([]filterDocument, error) {
+	var documents []filterDocument
+
+	for _, filter := range filters {
+		document, err := filterToDocument(filter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func filterToDocument(filter DQLizer) (filterDocument, error) {
+	switch cast := filter.(type) {
+	case *FilterFn:
+		return filterToDocument(cast.DQLizer)
+	case FilterFn:
+		return filterToDocument(cast.DQLizer)
+	case And:
+		return connectiveToDocument("and", cast)
+	case Or:
+		return connectiveToDocument("or", cast)
+	case Not:
+		return connectiveToDocument("not", cast)
+	case Eq:
+		return termsToDocument(eqFunc, filterKV(cast))
+	case Le:
+		return termsToDocument(leFunc, filterKV(cast))
+	case Lt:
+		return termsToDocument(ltFunc, filterKV(cast))
+	case Ge:
+		return termsToDocument(geFunc, filterKV(cast))
+	case Gt:
+		return termsToDocument(gtFunc, filterKV(cast))
+	case AllOfTerms:
+		return termsToDocument(alloftermsFunc, filterKV(cast))
+	case AnyOfTerms:
+		return termsToDocument(anyoftermsFunc, filterKV(cast))
+	case Match:
+		return termsToDocument(matchFunc, filterKV(cast))
+	case AllOfText:
+		return termsToDocument(alloftextFunc, filterKV(cast))
+	case AnyOfText:
+		return termsToDocument(anyoftextFunc, filterKV(cast))
+	case Exact:
+		return termsToDocument(exactFunc, filterKV(cast))
+	case Term:
+		return termsToDocument(termFunc, filterKV(cast))
+	case FullText:
+		return termsToDocument(fulltextFunc, filterKV(cast))
+	case UIDIn:
+		return termsToDocument(uidInFunc, filterKV(cast))
+	case Regexp:
+		patterns := filterKV{}
+		for predicate, pattern := range cast {
+			patterns[predicate] = pattern
+		}
+		return termsToDocument(regexpFunc, patterns)
+	case between:
+		args, err := valuesToDocuments([]interface{}{cast.from, cast.to})
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		return filterDocument{Func: string(betweenFunc), Predicate: cast.predicate, Args: args}, nil
+	case filterExpr:
+		if cast.predicate != "" {
+			return filterDocument{Func: string(cast.funcType), Predicate: cast.predicate}, nil
+		}
+
+		if values, ok := cast.value.(filterKV); ok {
+			return termsToDocument(cast.funcType, values)
+		}
+
+		value, err := valueToDocument(cast.value)
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		return filterDocument{Func: string(cast.funcType), Value: &value}, nil
+	case functionExpr:
+		args, err := valuesToDocuments(cast.args)
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		return filterDocument{Func: string(cast.funcType), Args: args}, nil
+	case SafeRegexp:
+		value, err := valueToDocument(cast.String())
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		return filterDocument{Func: string(regexpFunc), Predicate: cast.predicate, Value: &value}, nil
+	case RawExpression:
+		if matches := countExpressionPattern.FindStringSubmatch(cast.Val); matches != nil {
+			return filterDocument{Func: string(countFunc), Predicate: matches[1]}, nil
+		}
+
+		if matches := aggregateExpressionPattern.FindStringSubmatch(cast.Val); matches != nil {
+			return filterDocument{Func: matches[1], Predicate: matches[2]}, nil
+		}
+
+		return filterDocument{DQL: cast.Val}, nil
+	case statement:
+		argsDoc, err := valuesToDocuments(cast.args)
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		return filterDocument{DQL: cast.query, Args: argsDoc}, nil
+	}
+
+	return filterDocument{}, fmt.Errorf("cannot serialize filter of type %T", filter)
+}
+
+func connectiveToDocument(operator string, filters []DQLizer) (filterDocument, error) {
+	documents, err := filtersToDocuments(filters)
+
+	if err != nil {
+		return filterDocument{}, err
+	}
+
+	return filterDocument{Operator: operator, Filters: documents}, nil
+}
+
+func termsToDocument(funcType FuncType, values filterKV) (filterDocument, error) {
+	document := filterDocument{Func: string(funcType)}
+
+	for _, predicate := range getSortedKeys(values) {
+		value, err := valueToDocument(values[predicate])
+
+		if err != nil {
+			return filterDocument{}, err
+		}
+
+		document.Terms = append(document.Terms, termDocument{Predicate: predicate, Value: value})
+	}
+
+	return document, nil
+}
+
+func orderToDocument(order DQLizer) (orderDocument, error) {
+	cast, ok := order.(orderBy)
+
+	if !ok {
+		filter, err := filterToDocument(order)
+		return orderDocument{Filter: &filter}, err
+	}
+
+	document := orderDocument{Direction: cast.Direction}
+
+	if predicate, ok := cast.Predicate.(string); ok {
+		document.Predicate = predicate
+		return document, nil
+	}
+
+	value, err := valueToDocument(cast.Predicate)
+
+	if err != nil {
+		return document, err
+	}
+
+	document.Value = &value
+	return document, nil
+}
+
+func selectionsToDocuments(selections []interface{}) ([]selectionDocument, error) {
+	var documents []selectionDocument
+
+	for _, selection := range selections {
+		document, err := selectionToDocument(selection)
+
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func selectionToDocument(selection interface{}) (selectionDocument, error) {
+	switch cast := selection.(type) {
+	case string:
+		return selectionDocument{Field: cast}, nil
+	case aliasField:
+		of, err := selectionToDocument(cast.value)
+		return selectionDocument{Alias: cast.alias, Of: &of}, err
+	case as:
+		of, err := selectionToDocument(cast.predicate)
+		return selectionDocument{Variable: cast.variable, Of: &of}, err
+	case DQLizer:
+		filter, err := filterToDocument(cast)
+		return selectionDocument{Filter: &filter}, err
+	}
+
+	return selectionDocument{}, fmt.Errorf("cannot serialize selection of type %T", selection)
+}
+
+func valuesToDocuments(values []interface{}) ([]valueDocument, error) {
+	var documents []valueDocument
+
+	for _, value := range values {
+		document, err := valueToDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func valueToDocument(value interface{}) (valueDocument, error) {
+	var valueType string
+
+	switch cast := value.(type) {
+	case string:
+		valueType = "string"
+	case int:
+		valueType = "int"
+	case int8:
+		valueType = "int8"
+	case int16:
+		valueType = "int16"
+	case int32:
+		valueType = "int32"
+	case int64:
+		valueType = "int64"
+	case uint:
+		valueType = "uint"
+	case uint8:
+		valueType = "uint8"
+	case uint16:
+		valueType = "uint16"
+	case uint32:
+		valueType = "uint32"
+	case uint64:
+		valueType = "uint64"
+	case float32:
+		valueType = "float32"
+	case float64:
+		valueType = "float64"
+	case bool:
+		valueType = "bool"
+	case time.Time:
+		valueType = "datetime"
+		value = cast.Format(time.RFC3339Nano)
+	case *time.Time:
+		valueType = "datetime"
+		value = cast.Format(time.RFC3339Nano)
+	case RawExpression:
+		if predicate, ok := unescapePredicate(cast.Val); ok && isPlainPredicate(predicate) {
+			valueType = "predicate"
+			value = predicate
+			break
+		}
+
+		if strictVariablePattern.MatchString(cast.Val) {
+			valueType = "variable"
+			value = cast.Val
+			break
+		}
+
+		if countExpressionPattern.MatchString(cast.Val) || aggregateExpressionPattern.MatchString(cast.Val) {
+			filter, err := filterToDocument(cast)
+			return valueDocument{Type: "filter", Filter: &filter}, err
+		}
+
+		valueType = "expr"
+		value = cast.Val
+	case DQLizer:
+		filter, err := filterToDocument(cast)
+		return valueDocument{Type: "filter", Filter: &filter}, err
+	default:
+		if !isListType(value) {
+			return valueDocument{}, fmt.Errorf("cannot serialize value of type %T", value)
+		}
+
+		values, err := toInterfaceSlice(value)
+
+		if err != nil {
+			return valueDocument{}, err
+		}
+
+		items, err := valuesToDocuments(values)
+
+		if items == nil {
+			items = []valueDocument{}
+		}
+
+		return valueDocument{Type: "list", Items: items}, err
+	}
+
+	raw, err := json.Marshal(value)
+
+	if err != nil {
+		return valueDocument{}, err
+	}
+
+	return valueDocument{Type: valueType, Value: raw}, nil
+}
+
+func (decoder QueryDecoder) queryFromDocument(document queryDocument) (QueryBuilder, error) {
+	edgeDoc := document.Query
+
+	var builder QueryBuilder
+
+	if edgeDoc.IsVariable {
+		builder = Variable(nil)
+	} else {
+		builder = Query(nil)
+	}
+
+	if edgeDoc.Name != "" {
+		builder = builder.Name(edgeDoc.Name)
+	}
+
+	if edgeDoc.RootFilter != nil {
+		rootFilter, err := decoder.filterFromDocument(*edgeDoc.RootFilter)
+
+		if err != nil {
+			return builder, err
+		}
+
+		builder.rootEdge.RootFilter = FilterFn{rootFilter}
+	}
+
+	builder, err := decoder.applyEdgeDocument(builder, edgeDoc)
+
+	if err != nil {
+		return builder, err
+	}
+
+	if builder, err = decoder.addEdgeDocuments(builder, "", edgeDoc.Edges); err != nil {
+		return builder, err
+	}
+
+	for _, variableDoc := range document.Variables {
+		variable, err := decoder.queryFromDocument(variableDoc)
+
+		if err != nil {
+			return builder, err
+		}
+
+		builder = builder.Variable(variable)
+	}
+
+	builder.scopes = document.Scopes
+
+	return builder.As(edgeDoc.Alias), nil
+}
+
+func (decoder QueryDecoder) addEdgeDocuments(builder QueryBuilder, parentPath string, documents []edgeDocument) (QueryBuilder, error) {
+	for _, document := range documents {
+		if document.Name == "" {
+			return builder, fmt.Errorf("an edge of '%s' has no name", builder.rootEdge.Name)
+		}
+
+		fullPath := document.Name
+
+		if parentPath != "" {
+			fullPath = EdgePath(parentPath, document.Name)
+		}
+
+		var err error
+
+		builder = builder.EdgeFnAs(document.Alias, fullPath, func(edgeBuilder QueryBuilder) QueryBuilder {
+			edgeBuilder, err = decoder.applyEdgeDocument(edgeBuilder, document)
+			return edgeBuilder
+		})
+
+		if err != nil {
+			return builder, fmt.Errorf("edge %s: %w", fullPath, err)
+		}
+
+		if builder, err = decoder.addEdgeDocuments(builder, fullPath, document.Edges); err != nil {
+			return builder, err
+		}
+	}
+
+	return builder, nil
+}
+
+func (decoder QueryDecoder) applyEdgeDocument(builder QueryBuilder, document edgeDocument) (QueryBuilder, error) {
+	selections, err := decoder.selectionsFromDocuments(document.Select)
+
+	if err != nil {
+		return builder, err
+	}
+
+	builder = builder.Select(selections...)
+
+	for _, filterDoc := range document.Filters {
+		filter, err := decoder.filterFromDocument(filterDoc)
+
+		if err != nil {
+			return builder, err
+		}
+
+		builder = builder.Filter(filter)
+	}
+
+	if document.Pagination != nil {
+		builder = builder.Paginate(Cursor{
+			First:  document.Pagination.First,
+			Offset: document.Pagination.Offset,
+			After:  document.Pagination.After,
+		})
+	}
+
+	for _, orderDoc := range document.Order {
+		order, err := decoder.orderFromDocument(orderDoc)
+
+		if err != nil {
+			return builder, err
+		}
+
+		builder = builder.Order(order)
+	}
+
+	builder = builder.GroupBy(document.GroupBy...)
+
+	for _, facetsDoc := range document.Facets {
+		facets, err := decoder.selectionsFromDocuments(facetsDoc)
+
+		if err != nil {
+			return builder, err
+		}
+
+		builder = builder.Facets(facets...)
+	}
+
+	if document.Cascade != nil {
+		builder = builder.Cascade(document.Cascade.Fields...)
+	}
+
+	return builder, nil
+}
+
+func (decoder QueryDecoder) filtersFromDocuments(documents []filterDocument) ([]DQLizer, error) {
+	filters := make([]DQLizer, 0, len(documents))
+
+	for _, document := range documents {
+		filter, err := decoder.filterFromDocument(document)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func (decoder QueryDecoder) filterFromDocument(document filterDocument) (DQLizer, error) {
+	if document.Operator != "" {
+		filters, err := decoder.filtersFromDocuments(document.Filters)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(document.Operator) {
+		case "and":
+			return And(filters), nil
+		case "or":
+			return Or(filters), nil
+		case "not":
+			return Not(filters), nil
+		}
+
+		return nil, fmt.Errorf("unknown filter operator '%s'", document.Operator)
+	}
+
+	args, err := decoder.valuesFromDocuments(document.Args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if document.DQL != "" {
+		raw, err := decoder.rawDQL(document.DQL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		placeholders := strings.Count(document.DQL, symbolValuePlaceholder)
+
+		if placeholders != len(args) {
+			return nil, fmt.Errorf("the statement '%s' has %d placeholders, given %d arguments", document.DQL, placeholders, len(args))
+		}
+
+		if len(args) == 0 {
+			return raw, nil
+		}
+
+		return statement{query: raw.Val, args: args}, nil
+	}
+
+	if document.Func == "" {
+		return nil, fmt.Errorf("a filter requires an operator, a function or a statement")
+	}
+
+	if !documentFuncPattern.MatchString(document.Func) {
+		return nil, fmt.Errorf("invalid function '%s'", document.Func)
+	}
+
+	funcType := FuncType(document.Func)
+
+	if document.Predicate != "" && document.Value == nil && len(document.Args) == 0 && len(document.Terms) == 0 {
+		return predicateFuncFromDocument(funcType, document.Predicate)
+	}
+
+	switch {
+	case len(document.Terms) > 0:
+		values := filterKV{}
+
+		for _, term := range document.Terms {
+			value, err := decoder.valueFromDocument(term.Value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			values[term.Predicate] = value
+		}
+
+		if funcType == regexpFunc {
+			return regexpFromTerms(values)
+		}
+
+		if filter, ok := newFilterKV(funcType, values); ok {
+			return filter, nil
+		}
+
+		return filterExpr{funcType: funcType, value: values}, nil
+	case funcType == betweenFunc && document.Predicate != "":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("between expects 2 arguments, given %d", len(args))
+		}
+
+		return Between(document.Predicate, args[0], args[1]), nil
+	case funcType == regexpFunc && document.Predicate != "" && document.Value != nil:
+		value, err := decoder.valueFromDocument(*document.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, ok := value.(string)
+
+		if !ok {
+			return nil, fmt.Errorf("regexp expects a string pattern for '%s'", document.Predicate)
+		}
+
+		return NewRegexp(document.Predicate, pattern)
+	case document.Value != nil:
+		value, err := decoder.valueFromDocument(*document.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return filterExpr{funcType: funcType, value: value}, nil
+	}
+
+	return functionExpr{funcType: funcType, args: args}, nil
+}
+
+func regexpFromTerms(values filterKV) (DQLizer, error) {
+	patterns := Regexp{}
+
+	for predicate, value := range values {
+		pattern, ok := value.(string)
+
+		if !ok {
+			return nil, fmt.Errorf("regexp expects a string pattern for '%s'", predicate)
+		}
+
+		// the pattern is inlined, its delimiters must be escaped
+		expression, err := NewRegexp(predicate, pattern)
+
+		if err != nil {
+			return nil, err
+		}
+
+		patterns[predicate] = expression.String()
+	}
+
+	return patterns, nil
+}
+
+// predicateFuncFromDocument restores the functions applied to a name
+func predicateFuncFromDocument(funcType FuncType, predicate string) (DQLizer, error) {
+	pattern := strictPredicatePattern
+
+	switch funcType {
+	case typeFunc:
+		pattern = strictAliasPattern
+	case valFunc, sumFunc, "avg", "min", "max":
+		pattern = strictVariablePattern
+	}
+
+	if !pattern.MatchString(predicate) {
+		return nil, fmt.Errorf("invalid name '%s' for function %s", predicate, funcType)
+	}
+
+	switch funcType {
+	case hasFunc:
+		return HasFn(predicate), nil
+	case typeFunc:
+		return TypeFn(predicate), nil
+	case valFunc:
+		return Val(predicate), nil
+	case countFunc:
+		return Count(predicate), nil
+	}
+
+	if aggregate, ok := aggregateFuncs[string(funcType)]; ok {
+		return aggregate(predicate), nil
+	}
+
+	return nil, fmt.Errorf("function %s can't be applied to a predicate", funcType)
+}
+
+func (decoder QueryDecoder) orderFromDocument(document orderDocument) (DQLizer, error) {
+	if document.Filter != nil {
+		return decoder.filterFromDocument(*document.Filter)
+	}
+
+	if document.Direction != OrderDirectionAsc && document.Direction != OrderDirectionDesc {
+		return nil, fmt.Errorf("invalid order direction '%s'", document.Direction)
+	}
+
+	if document.Value == nil {
+		return orderBy{Direction: document.Direction, Predicate: document.Predicate}, nil
+	}
+
+	value, err := decoder.valueFromDocument(*document.Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return orderBy{Direction: document.Direction, Predicate: value}, nil
+}
+
+func (decoder QueryDecoder) selectionsFromDocuments(documents []selectionDocument) ([]interface{}, error) {
+	selections := make([]interface{}, 0, len(documents))
+
+	for _, document := range documents {
+		selection, err := decoder.selectionFromDocument(document)
+
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+
+	return selections, nil
+}
+
+func (decoder QueryDecoder) selectionFromDocument(document selectionDocument) (interface{}, error) {
+	switch {
+	case document.Of != nil:
+		of, err := decoder.selectionFromDocument(*document.Of)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if document.Alias != "" {
+			return Alias(document.Alias, of), nil
+		}
+
+		return As(document.Variable, of), nil
+	case document.Filter != nil:
+		return decoder.filterFromDocument(*document.Filter)
+	}
+
+	return document.Field, nil
+}
+
+func (decoder QueryDecoder) valuesFromDocuments(documents []valueDocument) ([]interface{}, error) {
+	var values []interface{}
+
+	for _, document := range documents {
+		value, err := decoder.valueFromDocument(document)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func (decoder QueryDecoder) valueFromDocument(document valueDocument) (interface{}, error) {
+	var value interface{}
+
+	switch document.Type {
+	case "string":
+		value = new(string)
+	case "int":
+		value = new(int)
+	case "int8":
+		value = new(int8)
+	case "int16":
+		value = new(int16)
+	case "int32":
+		value = new(int32)
+	case "int64":
+		value = new(int64)
+	case "uint":
+		value = new(uint)
+	case "uint8":
+		value = new(uint8)
+	case "uint16":
+		value = new(uint16)
+	case "uint32":
+		value = new(uint32)
+	case "uint64":
+		value = new(uint64)
+	case "float32":
+		value = new(float32)
+	case "float64":
+		value = new(float64)
+	case "bool":
+		value = new(bool)
+	case "datetime":
+		var raw string
+
+		if err := json.Unmarshal(document.Value, &raw); err != nil {
+			return nil, err
+		}
+
+		return time.Parse(time.RFC3339Nano, raw)
+	case "predicate", "variable", "expr":
+		var raw string
+
+		if err := json.Unmarshal(document.Value, &raw); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case document.Type == "predicate" && isPlainPredicate(raw):
+			return Predicate(raw), nil
+		case document.Type == "variable" && strictVariablePattern.MatchString(raw):
+			return Expr(raw), nil
+		case document.Type == "expr":
+			return decoder.rawDQL(raw)
+		}
+
+		return nil, fmt.Errorf("invalid %s '%s'", document.Type, raw)
+	case "filter":
+		if document.Filter == nil {
+			return nil, fmt.Errorf("a filter value requires a filter")
+		}
+
+		return decoder.filterFromDocument(*document.Filter)
+	case "list":
+		values, err := decoder.valuesFromDocuments(document.Items)
+
+		if values == nil {
+			values = []interface{}{}
+		}
+
+		return values, err
+	default:
+		return nil, fmt.Errorf("unknown value type '%s'", document.Type)
+	}
+
+	if err := json.Unmarshal(document.Value, value); err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", document.Type, err)
+	}
+
+	// dereference the decoded pointer
+	return reflect.ValueOf(value).Elem().Interface(), nil
+}
@@ -0,0 +1,271 @@
+package dqlx_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_JSON_Round_Trip(t *testing.T) {
+	from := time.Date(2021, 1, 1, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	safeRegexp, err := dql.NewRegexp("path", "^/home/")
+	require.NoError(t, err)
+
+	variable := dql.Variable(dql.EqFn("name", "test")).
+		Edge("film", dql.Fields(`
+			D as initial_release_date
+		`), dql.GroupBy("genre"))
+
+	builders := map[string]dql.QueryBuilder{
+		"nested": dql.
+			QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+			Fields(`
+				uid
+				name
+			`).
+			Filter(dql.Or{
+				dql.Eq{"name": "actor1"},
+				dql.And{dql.Gt{"age": int64(20)}, dql.Le{"score": 4.5}},
+			}).
+			Edge("actors", dql.Fields(`
+				uid
+				surname
+			`), dql.Cursor{First: 10, Offset: 2, After: "0x1"}, dql.Facets("since", dql.Eq{"close": true})).
+			Edge("actors->rewards", dql.Fields(`
+				points
+			`), dql.OrderDesc("points"), dql.Cascade("points")).
+			EdgeAs("V", "actors->rewards->venues", dql.Fields(`
+				street
+			`)),
+		"variables": dql.
+			QueryEdge("bladerunner", dql.TypeFn("Film")).
+			Fields(
+				"uid",
+				dql.Alias("total", dql.Count("actors")),
+				dql.As("A", dql.Val("D")),
+			).
+			Variable(variable).
+			Filter(dql.UID(dql.Val("D"))).
+			OrderAsc(dql.Val("D")).
+			Cascade().
+			As("B"),
+		"functions": dql.
+			QueryEdge("bladerunner", dql.UIDFn([]string{"0x1", "0x2"})).
+			Fields(`
+				uid
+			`).
+			Filter(dql.UIDIn{"name": "value1"}).
+			Filter(dql.Between("release_date", from, to)).
+			Filter(dql.Regexp{"name": "/^Steven.*$/i"}).
+			Filter(dql.Not{dql.Eq{"deleted": true}}).
+			Filter(dql.AnyOfTerms{"tags": []string{"a", "b"}}).
+			Filter(dql.Has("email")).
+			Filter(safeRegexp).
+			Select(dql.Alias("total", dql.Sum("D"))),
+	}
+
+	for name, builder := range builders {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(builder)
+			require.NoError(t, err)
+
+			var restored dql.QueryBuilder
+			require.NoError(t, json.Unmarshal(data, &restored))
+
+			expectedQuery, expectedVariables, err := builder.ToDQL()
+			require.NoError(t, err)
+
+			query, variables, err := restored.ToDQL()
+			require.NoError(t, err)
+
+			require.Equal(t, expectedQuery, query)
+			require.Equal(t, expectedVariables, variables)
+
+			// the document is stable
+			again, err := json.Marshal(restored)
+			require.NoError(t, err)
+			require.JSONEq(t, string(data), string(again))
+		})
+	}
+}
+
+func Test_Query_JSON_Document(t *testing.T) {
+	builder := dql.QueryType("User").
+		Select("name").
+		Filter(dql.Gt{"age": 18}).
+		Scopes(dql.NewScope("paginated", func(builder dql.QueryBuilder) dql.QueryBuilder {
+			return builder.Paginate(dql.Cursor{First: 10})
+		})).
+		Edge("friends", dql.Select("name"))
+
+	data, err := json.Marshal(builder)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"version": 1,
+		"query": {
+			"name": "rootQuery",
+			"func": {"func": "type", "predicate": "User"},
+			"filters": [
+				{"func": "gt", "terms": [{"predicate": "age", "value": {"type": "int", "value": 18}}]}
+			],
+			"pagination": {"first": 10},
+			"select": [{"field": "name"}],
+			"edges": [
+				{"name": "friends", "select": [{"field": "name"}]}
+			]
+		},
+		"scopes": ["paginated"]
+	}`, string(data))
+
+	var restored dql.QueryBuilder
+	require.NoError(t, json.Unmarshal(data, &restored))
+	require.True(t, restored.HasScope("paginated"))
+
+	// the restored query can be edited
+	_, variables, err := restored.Filter(dql.Lt{"age": 65}).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"$0": "10",
+		"$1": "18",
+		"$2": "65",
+	}, variables)
+}
+
+func Test_Query_JSON_Keeps_Value_Types(t *testing.T) {
+	date := time.Date(2021, 1, 1, 10, 30, 0, 500, time.UTC)
+
+	builder := dql.Query(dql.EqFn("count", int64(5))).
+		Filter(dql.Eq{"score": float32(1.5), "date": date, "code": "10"})
+
+	data, err := json.Marshal(builder)
+	require.NoError(t, err)
+
+	var restored dql.QueryBuilder
+	require.NoError(t, json.Unmarshal(data, &restored))
+
+	ast := restored.AST()
+	require.Equal(t, []interface{}{int64(5)}, ast.Blocks[0].RootFilter.Args)
+
+	filters := ast.Blocks[0].Filters[0].Filters
+	require.Equal(t, []interface{}{"10"}, filters[0].Args)
+	require.Equal(t, []interface{}{date}, filters[1].Args)
+	require.Equal(t, []interface{}{float32(1.5)}, filters[2].Args)
+}
+
+func Test_Query_JSON_Errors(t *testing.T) {
+	var restored dql.QueryBuilder
+
+	err := json.Unmarshal([]byte(`{"version": 2, "query": {"name": "q"}}`), &restored)
+	require.EqualError(t, err, "unsupported query document version 2")
+
+	err = json.Unmarshal([]byte(`{"version": 1, "query": {"name": "q", "filters": [{"func": "eq", "terms": [{"predicate": "a", "value": {"type": "complex", "value": 1}}]}]}}`), &restored)
+	require.EqualError(t, err, "unknown value type 'complex'")
+
+	_, err = dql.UnmarshalQuery([]byte(`{"version": 1, "query": {"name": "q", "filters": [{"dql": "eq(a, ??)"}]}}`), dql.WithRawDQL(true))
+	require.EqualError(t, err, "the statement 'eq(a, ??)' has 1 placeholders, given 0 arguments")
+
+	_, err = dql.UnmarshalQuery([]byte(`{"version": 1, "query": {"name": "q", "filters": [{"dql": "eq(a, ??)", "args": [{"type": "int", "value": 1}, {"type": "int", "value": 2}]}]}}`), dql.WithRawDQL(true))
+	require.EqualError(t, err, "the statement 'eq(a, ??)' has 1 placeholders, given 2 arguments")
+
+	_, err = json.Marshal(dql.Query(nil).Filter(dql.Eq{"a": struct{}{}}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot serialize value of type struct {}")
+}
+
+func Test_Query_JSON_Raw_DQL(t *testing.T) {
+	builder := dql.QueryType("User").Filter(dql.Expr("has(<name>)")).Select("name")
+
+	data, err := json.Marshal(builder)
+	require.NoError(t, err)
+
+	var restored dql.QueryBuilder
+	err = json.Unmarshal(data, &restored)
+	require.EqualError(t, err, "raw DQL 'has(<name>)' is not allowed in query documents")
+
+	// raw statements are accepted on demand
+	restored, err = dql.UnmarshalQuery(data, dql.WithRawDQL(true))
+	require.NoError(t, err)
+
+	expected, _, err := builder.ToDQL()
+	require.NoError(t, err)
+
+	query, _, err := restored.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, expected, query)
+
+	// unknown expressions are not serialized as DQL
+	_, err = json.Marshal(dql.QueryType("User").Filter(dql.Len("v").Eq(0)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot serialize filter of type")
+}
+
+func Test_Query_JSON_Rejects_Tampered_Documents(t *testing.T) {
+	documents := map[string]struct {
+		filter   string
+		expected string
+	}{
+		"raw statement": {
+			filter:   `{"dql": "has(<name>)) { secret } q(func: has(<password>)"}`,
+			expected: "raw DQL 'has(<name>)) { secret } q(func: has(<password>)' is not allowed in query documents",
+		},
+		"raw value": {
+			filter:   `{"func": "uid", "value": {"type": "expr", "value": "0x1)) { secret } q(func: has(<password>"}}`,
+			expected: "raw DQL '0x1)) { secret } q(func: has(<password>' is not allowed in query documents",
+		},
+		"predicate value": {
+			filter:   `{"func": "uid", "value": {"type": "predicate", "value": "name> OR <password"}}`,
+			expected: "invalid predicate 'name> OR <password'",
+		},
+		"variable value": {
+			filter:   `{"func": "uid", "value": {"type": "variable", "value": "M) OR has(password"}}`,
+			expected: "invalid variable 'M) OR has(password'",
+		},
+		"function": {
+			filter:   `{"func": "has(<password>) OR eq", "terms": [{"predicate": "a", "value": {"type": "int", "value": 1}}]}`,
+			expected: "invalid function 'has(<password>) OR eq'",
+		},
+		"has": {
+			filter:   `{"func": "has", "predicate": "name } secret {"}`,
+			expected: "invalid name 'name } secret {' for function has",
+		},
+		"regexp": {
+			filter:   `{"func": "regexp", "terms": [{"predicate": "name", "value": {"type": "string", "value": "/(/"}}]}`,
+			expected: "regexp on 'name': error parsing regexp: missing closing ): `(`",
+		},
+		"term predicate": {
+			filter:   `{"func": "eq", "terms": [{"predicate": "a, 1) OR has(password", "value": {"type": "int", "value": 1}}]}`,
+			expected: "invalid predicate 'a, 1) OR has(password' in q",
+		},
+	}
+
+	for name, document := range documents {
+		t.Run(name, func(t *testing.T) {
+			var restored dql.QueryBuilder
+
+			err := json.Unmarshal([]byte(`{"version": 1, "query": {"name": "q", "filters": [`+document.filter+`]}}`), &restored)
+			require.EqualError(t, err, document.expected)
+		})
+	}
+
+	var restored dql.QueryBuilder
+
+	err := json.Unmarshal([]byte(`{"version": 1, "query": {"name": "q", "order": [{"direction": "orderasc: name) { secret } q(func: has(password)", "predicate": "name"}]}}`), &restored)
+	require.EqualError(t, err, "invalid order direction 'orderasc: name) { secret } q(func: has(password)'")
+
+	err = json.Unmarshal([]byte(`{"version": 1, "query": {"name": "q", "edges": [{"name": "friends", "alias": "F } secret { x"}]}}`), &restored)
+	require.EqualError(t, err, "invalid variable 'F } secret { x' in q->friends")
+
+	// the delimiters of inlined regular expressions are escaped
+	err = json.Unmarshal([]byte(`{"version": 1, "query": {"name": "q", "filters": [{"func": "regexp", "terms": [{"predicate": "name", "value": {"type": "string", "value": "/(/) OR has(password) OR regexp(a, /)/"}}]}]}}`), &restored)
+	require.NoError(t, err)
+
+	query, _, err := restored.ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, `@filter(regexp(<name>,/(\/) OR has(password) OR regexp(a, \/)/))`)
+}
@@ -1,7 +1,6 @@
 package dqlx
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 )
@@ -14,9 +13,23 @@ type queryOperation struct {
 // QueriesToDQL returns the DQL statement for 1 or more queries
 // Example: dqlx.QueriesToDQL(query1,query2,query3)
 func QueriesToDQL(queries ...QueryBuilder) (query string, args map[string]string, err error) {
+	mainOperation, err := newQueryOperation(queries)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return mainOperation.ToDQL()
+}
+
+func newQueryOperation(queries []QueryBuilder) (queryOperation, error) {
 	mainOperation := queryOperation{}
 	queries = ensureUniqueQueryNames(queries)
 
+	if err := validateStrictQueries(queries); err != nil {
+		return queryOperation{}, err
+	}
+
 	for _, query := range queries {
 		mainOperation.operations = append(mainOperation.operations, query.rootEdge)
 
@@ -25,44 +38,69 @@ func QueriesToDQL(queries ...QueryBuilder) (query string, args map[string]string
 		}
 	}
 
-	return mainOperation.ToDQL()
+	return mainOperation, nil
 }
 
 // ToDQL returns the DQL statement for 1 or more queries
 func (grammar queryOperation) ToDQL() (query string, variables map[string]string, err error) {
-	variables = map[string]string{}
-	blocNames := make([]string, len(grammar.operations))
+	writer, err := grammar.render()
 
-	for index, block := range grammar.operations {
-		blocNames[index] = strings.Title(strings.ToLower(block.GetName()))
+	if err != nil {
+		return "", nil, err
 	}
 
-	queryName := strings.Join(blocNames, "_")
+	for _, arg := range writer.args {
+		if parameter, ok := arg.(Parameter); ok {
+			return "", nil, fmt.Errorf("parameter %s can only be used in prepared queries", parameter.Name)
+		}
+	}
 
-	var args []interface{}
-	var statements []string
+	variables, placeholders := toVariables(writer.args)
 
-	if err := addOperation(grammar.variables, &statements, &args); err != nil {
-		return "", nil, err
+	return grammar.document(placeholders, writer), variables, nil
+}
+
+// render writes the blocks of the operation
+func (grammar queryOperation) render() (*dqlWriter, error) {
+	writer := &dqlWriter{}
+
+	if err := addOperation(writer, grammar.variables); err != nil {
+		return nil, err
 	}
 
-	if err := addOperation(grammar.operations, &statements, &args); err != nil {
-		return "", nil, err
+	if len(grammar.variables) > 0 && len(grammar.operations) > 0 {
+		writer.WriteString(" ")
+	}
+
+	if err := addOperation(writer, grammar.operations); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// document returns the query document of the rendered blocks
+func (grammar queryOperation) document(placeholders []string, writer *dqlWriter) string {
+	blocNames := make([]string, len(grammar.operations))
+
+	for index, block := range grammar.operations {
+		blocNames[index] = strings.Title(strings.ToLower(block.GetName()))
 	}
 
-	innerQuery := strings.Join(statements, " ")
+	queryName := strings.Join(blocNames, "_")
 
-	query, rawVariables := replacePlaceholders(innerQuery, args, func(index int, value interface{}) string {
-		return fmt.Sprintf("$%d", index)
-	})
-	variables, placeholders := toVariables(rawVariables)
+	builder := strings.Builder{}
+	builder.Grow(writer.buffer.Len() + len(queryName) + 32)
 
-	writer := bytes.Buffer{}
-	writer.WriteString(fmt.Sprintf("query %s(%s) {", queryName, strings.Join(placeholders, ", ")))
-	writer.WriteString(" " + query)
-	writer.WriteString(" }")
+	builder.WriteString("query ")
+	builder.WriteString(queryName)
+	builder.WriteString("(")
+	builder.WriteString(strings.Join(placeholders, ", "))
+	builder.WriteString(") { ")
+	builder.WriteString(writer.String())
+	builder.WriteString(" }")
 
-	return writer.String(), variables, nil
+	return builder.String()
 }
 
 func ensureUniqueQueryNames(queries []QueryBuilder) []QueryBuilder {
@@ -81,12 +119,16 @@ func ensureUniqueQueryNames(queries []QueryBuilder) []QueryBuilder {
 	return uniqueQueries
 }
 
-func addOperation(operations []edge, statements *[]string, args *[]interface{}) error {
-	parts := make([]DQLizer, len(operations))
-
+func addOperation(writer *dqlWriter, operations []edge) error {
 	for index, operation := range operations {
-		parts[index] = operation
+		if index > 0 {
+			writer.WriteString(" ")
+		}
+
+		if err := operation.renderDQL(writer); err != nil {
+			return err
+		}
 	}
 
-	return addStatement(parts, statements, args)
+	return nil
 }
@@ -0,0 +1,324 @@
+package dqlx
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NamedQuery a validated DQL query document with named parameters
+type NamedQuery struct {
+	Name       string
+	Query      string
+	Parameters []QueryParameter
+}
+
+// QueryRegistry holds named queries, usually loaded from .dql files at startup.
+// Queries are registered once, the registry is safe for concurrent lookups
+// after registration.
+type QueryRegistry struct {
+	queries map[string]NamedQuery
+}
+
+// NewQueryRegistry creates an empty QueryRegistry
+func NewQueryRegistry() *QueryRegistry {
+	return &QueryRegistry{
+		queries: map[string]NamedQuery{},
+	}
+}
+
+// LoadQueries loads every .dql file of the file system into a registry,
+// queries are named after their path without the extension
+//
+// Example:
+//   //go:embed queries
+//   var queries embed.FS
+//
+//   registry, err := dqlx.LoadQueries(queries)
+//   response, err := registry.Execute(ctx, "queries/top_films", map[string]interface{}{
+//     "director": "Ridley Scott",
+//     "since":    time.Now(),
+//   }, dqlx.WithClient(db.GetDgraph()))
+func LoadQueries(fsys fs.FS) (*QueryRegistry, error) {
+	registry := NewQueryRegistry()
+
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || path.Ext(filePath) != ".dql" {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		if err := registry.Register(strings.TrimSuffix(filePath, ".dql"), string(content)); err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Register validates and registers a query under the given name
+//
+// Example:
+//   registry.Register("users_by_name", `query Users($name: string) { ... }`)
+func (registry *QueryRegistry) Register(name string, query string) error {
+	if _, ok := registry.queries[name]; ok {
+		return fmt.Errorf("query '%s' already registered", name)
+	}
+
+	namedQuery, err := NewNamedQuery(name, query)
+
+	if err != nil {
+		return err
+	}
+
+	registry.queries[name] = namedQuery
+	return nil
+}
+
+// Get returns the query registered under the given name
+func (registry *QueryRegistry) Get(name string) (NamedQuery, bool) {
+	query, ok := registry.queries[name]
+	return query, ok
+}
+
+// Names returns the names of the registered queries in alphabetical order
+func (registry *QueryRegistry) Names() []string {
+	names := make([]string, 0, len(registry.queries))
+
+	for name := range registry.queries {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Execute binds the parameters and executes the named query
+func (registry *QueryRegistry) Execute(ctx context.Context, name string, parameters map[string]interface{}, options ...OperationExecutorOptionFn) (*Response, error) {
+	query, ok := registry.Get(name)
+
+	if !ok {
+		return nil, fmt.Errorf("query '%s' is not registered", name)
+	}
+
+	return query.Execute(ctx, parameters, options...)
+}
+
+// NewNamedQuery validates a query document: the syntax, the declared
+// parameters and their usage within the query blocks.
+// The query blocks are parsed on execution when query limits are set
+func NewNamedQuery(name string, query string) (NamedQuery, error) {
+	tokens, err := tokenize(query, false)
+
+	if err != nil {
+		return NamedQuery{}, err
+	}
+
+	parser := &dqlParser{
+		tokens:     tokens,
+		parameters: map[string]QueryParameter{},
+	}
+
+	document := &ParsedDocument{}
+
+	if err := parser.parseHeader(document); err != nil {
+		return NamedQuery{}, err
+	}
+
+	if err := parser.validateBody(document); err != nil {
+		return NamedQuery{}, err
+	}
+
+	return NamedQuery{
+		Name:       name,
+		Query:      query,
+		Parameters: document.Parameters,
+	}, nil
+}
+
+// validateBody makes sure brackets are balanced and
+// every declared variable is used, and only those
+func (parser *dqlParser) validateBody(document *ParsedDocument) error {
+	if _, err := parser.expect(tokenLeftCurl, "'{'"); err != nil {
+		return err
+	}
+
+	closing := map[tokenType]tokenType{
+		tokenLeftCurl:   tokenRightCurl,
+		tokenLeftParen:  tokenRightParen,
+		tokenLeftSquare: tokenRightSquare,
+	}
+
+	stack := []tokenType{tokenRightCurl}
+	used := map[string]bool{}
+	blocks := 0
+
+	for len(stack) > 0 {
+		tok := parser.next()
+
+		switch tok.Type {
+		case tokenEOF:
+			return parser.unexpected(tok, "a closing bracket")
+		case tokenLeftCurl, tokenLeftParen, tokenLeftSquare:
+			if tok.Type == tokenLeftCurl && len(stack) == 1 {
+				blocks++
+			}
+			stack = append(stack, closing[tok.Type])
+		case tokenRightCurl, tokenRightParen, tokenRightSquare:
+			if stack[len(stack)-1] != tok.Type {
+				return parser.errorf(tok, "unbalanced '%s'", tok.Value)
+			}
+			stack = stack[:len(stack)-1]
+		case tokenVariable:
+			if _, ok := parser.parameters[tok.Value]; !ok {
+				return parser.errorf(tok, "variable %s is not declared", tok.Value)
+			}
+			used[tok.Value] = true
+		}
+	}
+
+	if _, err := parser.expect(tokenEOF, "end of input"); err != nil {
+		return err
+	}
+
+	if blocks == 0 {
+		return parser.errorf(parser.peek(), "the document doesn't contain any query block")
+	}
+
+	for _, parameter := range document.Parameters {
+		if !used[parameter.Name] {
+			return fmt.Errorf("variable %s is declared but not used", parameter.Name)
+		}
+	}
+
+	return nil
+}
+
+// Bind converts the parameters into GraphQL variables,
+// values must match the declared types. Parameters with a default value are optional.
+//
+// Example:
+//   variables, err := query.Bind(map[string]interface{}{"name": "alice", "first": 10})
+func (query NamedQuery) Bind(parameters map[string]interface{}) (map[string]string, error) {
+	declared := map[string]QueryParameter{}
+
+	for _, parameter := range query.Parameters {
+		declared[parameter.Name] = parameter
+	}
+
+	variables := map[string]string{}
+
+	for name, value := range parameters {
+		if !strings.HasPrefix(name, "$") {
+			name = "$" + name
+		}
+
+		parameter, ok := declared[name]
+
+		if !ok {
+			return nil, fmt.Errorf("query '%s' has no parameter %s", query.Name, name)
+		}
+
+		formatted, err := formatParameter(parameter.Type, value)
+
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+
+		variables[name] = formatted
+	}
+
+	for _, parameter := range query.Parameters {
+		if _, ok := variables[parameter.Name]; !ok && !parameter.HasDefault {
+			return nil, fmt.Errorf("missing value for parameter %s", parameter.Name)
+		}
+	}
+
+	return variables, nil
+}
+
+// Execute binds the parameters and executes the query,
+// nothing is sent to the executor when the parameters don't bind.
+// Query limits are checked against the parsed document, see ExecuteDQL
+//
+// Example:
+//   response, err := query.Execute(ctx, map[string]interface{}{
+//     "director": "Ridley Scott",
+//     "since":    time.Now(),
+//   }, dqlx.WithClient(db.GetDgraph()), dqlx.WithReadOnly(true))
+func (query NamedQuery) Execute(ctx context.Context, parameters map[string]interface{}, options ...OperationExecutorOptionFn) (*Response, error) {
+	variables, err := query.Bind(parameters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	executor := NewDGoExecutor(nil)
+
+	for _, option := range options {
+		option(executor)
+	}
+
+	return executor.ExecuteDQL(ctx, query.Query, variables)
+}
+
+// formatParameter formats the value as a GraphQL variable of the given type
+func formatParameter(dqlType string, value interface{}) (string, error) {
+	switch dqlType {
+	case "int":
+		switch cast := value.(type) {
+		case int:
+			return strconv.Itoa(cast), nil
+		case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return fmt.Sprintf("%d", cast), nil
+		}
+	case "float":
+		switch cast := value.(type) {
+		case float32:
+			return strconv.FormatFloat(float64(cast), 'f', -1, 32), nil
+		case float64:
+			return strconv.FormatFloat(cast, 'f', -1, 64), nil
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return fmt.Sprintf("%d", cast), nil
+		}
+	case "bool":
+		if cast, ok := value.(bool); ok {
+			return strconv.FormatBool(cast), nil
+		}
+	case "datetime":
+		switch cast := value.(type) {
+		case time.Time:
+			return cast.Format(time.RFC3339Nano), nil
+		case *time.Time:
+			if cast != nil {
+				return cast.Format(time.RFC3339Nano), nil
+			}
+		}
+	default:
+		if cast, ok := value.(string); ok {
+			return cast, nil
+		}
+	}
+
+	return "", fmt.Errorf("expected a value of type %s, given %T", dqlType, value)
+}
@@ -0,0 +1,240 @@
+package dqlx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+const topFilmsQuery = `
+	# films of a director ordered by release date
+	query TopFilms($director: string, $first: int = 10, $since: datetime) {
+		director as var(func: eq(name@en, $director))
+
+		films(func: uid(director)) @recurse(depth: 2) {
+			director.film(first: $first, orderdesc: initial_release_date) @filter(ge(initial_release_date, $since)) {
+				name@en
+			}
+		}
+	}
+`
+
+func Test_Load_Queries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"queries/top_films.dql":   {Data: []byte(topFilmsQuery)},
+		"queries/film/by_uid.dql": {Data: []byte(`query Film($uid: string) { film(func: uid($uid)) { uid } }`)},
+		"queries/README.md":       {Data: []byte(`not a query`)},
+	}
+
+	registry, err := dql.LoadQueries(fsys)
+	require.NoError(t, err)
+	require.Equal(t, []string{"queries/film/by_uid", "queries/top_films"}, registry.Names())
+
+	query, ok := registry.Get("queries/top_films")
+	require.True(t, ok)
+	require.Equal(t, topFilmsQuery, query.Query)
+	require.Equal(t, []dql.QueryParameter{
+		{Name: "$director", Type: "string"},
+		{Name: "$first", Type: "int", Default: "10", HasDefault: true},
+		{Name: "$since", Type: "datetime"},
+	}, query.Parameters)
+
+	fsys["queries/broken.dql"] = &fstest.MapFile{Data: []byte(`query Broken($a: int) { q(func: uid($b)) { uid } }`)}
+
+	_, err = dql.LoadQueries(fsys)
+	require.EqualError(t, err, "queries/broken.dql: line 1, column 37: variable $b is not declared")
+
+	var parseError *dql.ParseError
+	require.True(t, errors.As(err, &parseError))
+}
+
+func Test_Named_Query_Bind(t *testing.T) {
+	query, err := dql.NewNamedQuery("top_films", topFilmsQuery)
+	require.NoError(t, err)
+
+	since := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	variables, err := query.Bind(map[string]interface{}{
+		"director": "Ridley Scott",
+		"$since":   since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"$director": "Ridley Scott",
+		"$since":    "2000-01-01T00:00:00Z",
+	}, variables)
+
+	variables, err = query.Bind(map[string]interface{}{
+		"director": "Ridley Scott",
+		"first":    int64(5),
+		"since":    since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "5", variables["$first"])
+
+	_, err = query.Bind(map[string]interface{}{"director": "Ridley Scott"})
+	require.EqualError(t, err, "missing value for parameter $since")
+
+	_, err = query.Bind(map[string]interface{}{"director": "Ridley Scott", "since": since, "first": "5"})
+	require.EqualError(t, err, "parameter $first: expected a value of type int, given string")
+
+	_, err = query.Bind(map[string]interface{}{"director": "Ridley Scott", "since": since, "limit": 5})
+	require.EqualError(t, err, "query 'top_films' has no parameter $limit")
+}
+
+func Test_Named_Query_Validation(t *testing.T) {
+	cases := map[string]string{
+		`query Q($a: int) { q(func: uid(0x1)) { uid } }`:     "variable $a is declared but not used",
+		`query Q($a: int) { q(func: uid($a)) { uid }`:        "line 1, column 44: expected a closing bracket, found end of input",
+		`query Q($a: int) { q(func: uid($a)) { uid ) }`:      "line 1, column 43: unbalanced ')'",
+		`{ q(func: uid(0x1)) { uid } } }`:                    "line 1, column 31: expected end of input, found '}'",
+		`query Q($a: int, $a: int) { q(func: uid($a)) { } }`: "line 1, column 18: variable $a declared twice",
+		`query Q() { }`: "line 1, column 14: the document doesn't contain any query block",
+	}
+
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			_, err := dql.NewNamedQuery("q", input)
+			require.EqualError(t, err, expected)
+		})
+	}
+}
+
+func Test_Registry_Execute(t *testing.T) {
+	registry := dql.NewQueryRegistry()
+	require.NoError(t, registry.Register("top_films", topFilmsQuery))
+	require.EqualError(t, registry.Register("top_films", topFilmsQuery), "query 'top_films' already registered")
+
+	_, err := registry.Execute(context.Background(), "unknown", nil)
+	require.EqualError(t, err, "query 'unknown' is not registered")
+
+	_, err = registry.Execute(context.Background(), "top_films", map[string]interface{}{"director": 1})
+	require.EqualError(t, err, "parameter $director: expected a value of type string, given int")
+
+	_, err = registry.Execute(context.Background(), "top_films", map[string]interface{}{
+		"director": "Ridley Scott",
+		"since":    time.Now(),
+	})
+	require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+}
+
+func Test_Named_Query_Execute_Binds_Before_Executing(t *testing.T) {
+	query, err := dql.NewNamedQuery("top_films", topFilmsQuery)
+	require.NoError(t, err)
+
+	// a recording allowlist records every query reaching the executor
+	allowlist := dql.NewQueryAllowlist().Record(true)
+
+	_, err = query.Execute(context.Background(), map[string]interface{}{
+		"director": "Ridley Scott",
+		"first":    "10",
+	}, dql.WithQueryAllowlist(allowlist))
+	require.EqualError(t, err, "parameter $first: expected a value of type int, given string")
+	require.Empty(t, allowlist.Hashes())
+
+	_, err = query.Execute(context.Background(), map[string]interface{}{
+		"director": "Ridley Scott",
+		"first":    10,
+		"since":    time.Now(),
+	}, dql.WithQueryAllowlist(allowlist))
+	require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+	require.Equal(t, []string{dql.QueryHash(topFilmsQuery)}, allowlist.Hashes())
+}
+
+func Test_Named_Query_Execute_Limits(t *testing.T) {
+	query, err := dql.NewNamedQuery("users", `
+		query Users($name: string) {
+			users(func: eq(name, $name)) {
+				name
+				friends { name }
+			}
+		}
+	`)
+	require.NoError(t, err)
+
+	parameters := map[string]interface{}{"name": "alice"}
+
+	_, err = query.Execute(context.Background(), parameters, dql.WithQueryLimits(dql.QueryLimits{RequirePagination: true}))
+	require.EqualError(t, err, "query limit exceeded in users: pagination is required")
+
+	_, err = query.Execute(context.Background(), parameters, dql.WithQueryLimits(dql.QueryLimits{ForbiddenPredicates: []string{"friends"}}))
+	require.EqualError(t, err, "query limit exceeded in users->friends: the predicate 'friends' is forbidden")
+
+	_, err = query.Execute(context.Background(), parameters, dql.WithQueryLimits(dql.QueryLimits{MaxDepth: 1}))
+	require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+
+	// documents the parser doesn't support can't be checked
+	recurse, err := dql.NewNamedQuery("recurse", `
+		query Recurse($name: string) {
+			users(func: eq(name, $name)) @recurse(depth: 2) {
+				friends
+			}
+		}
+	`)
+	require.NoError(t, err)
+
+	_, err = recurse.Execute(context.Background(), parameters, dql.WithQueryLimits(dql.QueryLimits{MaxDepth: 1}))
+	require.EqualError(t, err, "cannot check the query limits: line 3, column 34: unsupported directive '@recurse'")
+}
+
+func ExampleLoadQueries() {
+	queries := fstest.MapFS{
+		"queries/top_films.dql": {Data: []byte(topFilmsQuery)},
+	}
+
+	db, err := dql.Connect("localhost:9080")
+
+	if err != nil {
+		panic(err)
+	}
+
+	registry, err := dql.LoadQueries(queries)
+
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := registry.Execute(context.Background(), "queries/top_films", map[string]interface{}{
+		"director": "Ridley Scott",
+		"first":    10,
+		"since":    time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, dql.WithClient(db.GetDgraph()))
+
+	if err != nil {
+		panic(err)
+	}
+
+	var films []map[string]interface{}
+	_ = response.Unmarshal(&films)
+}
+
+func ExampleNamedQuery_Execute() {
+	db, err := dql.Connect("localhost:9080")
+
+	if err != nil {
+		panic(err)
+	}
+
+	query, err := dql.NewNamedQuery("top_films", topFilmsQuery)
+
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := query.Execute(context.Background(), map[string]interface{}{
+		"director": "Ridley Scott",
+		"since":    time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, dql.WithClient(db.GetDgraph()), dql.WithReadOnly(true))
+
+	if err != nil {
+		panic(err)
+	}
+
+	var films []map[string]interface{}
+	_ = response.Unmarshal(&films)
+}
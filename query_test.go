@@ -1,6 +1,8 @@
 package dqlx_test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -230,6 +232,29 @@ func Test_Query_Connecting_Filter(t *testing.T) {
 	require.Equal(t, expected, query)
 }
 
+func Test_Query_Alias(t *testing.T) {
+	query, variables, err := dql.QueriesToDQL(
+		dql.Query(dql.EqFn("name", "Blade Runner")).Name("movies").As("M").Select("uid"),
+		dql.QueryEdge("bladerunner", dql.UIDFn(dql.Expr("M"))).Select("name"),
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"$0": "Blade Runner"}, variables)
+
+	expected := dql.Minify(`
+		query Movies_Bladerunner($0:string) {
+			M as <movies>(func: eq(<name>,$0)) {
+				<uid>
+			}
+			<bladerunner>(func: uid(M)) {
+				<name>
+			}
+		}
+	`)
+
+	require.Equal(t, expected, query)
+}
+
 func Test_Query_Pagination(t *testing.T) {
 	query, variables, err := dql.
 		QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
@@ -680,3 +705,127 @@ func Test_Cascade(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, query)
 }
+
+func Test_Query_Derived_Builders_Are_Independent(t *testing.T) {
+	base := dql.QueryType("User").
+		Select("name").
+		Filter(dql.Eq{"active": true}).
+		Edge("friends", dql.Select("name"))
+
+	withPosts := base.Edge("posts", dql.Select("title")).Filter(dql.Has("email"))
+	withGroups := base.Edge("groups", dql.Select("name")).Filter(dql.Has("phone"))
+
+	query, _, err := base.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:bool) {
+			<rootQuery>(func: type(<User>)) @filter(eq(<active>,$0)) {
+				<name> <friends> { <name> }
+			}
+		}
+	`), query)
+
+	query, _, err = withPosts.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:bool) {
+			<rootQuery>(func: type(<User>)) @filter(eq(<active>,$0) AND has(<email>)) {
+				<name> <friends> { <name> } <posts> { <title> }
+			}
+		}
+	`), query)
+
+	query, _, err = withGroups.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:bool) {
+			<rootQuery>(func: type(<User>)) @filter(eq(<active>,$0) AND has(<phone>)) {
+				<name> <friends> { <name> } <groups> { <name> }
+			}
+		}
+	`), query)
+}
+
+func Test_Query_Clone(t *testing.T) {
+	base := dql.QueryType("User").
+		Select("name").
+		Edge("friends", dql.Select("name")).
+		Edge("friends->posts", dql.Select("title"))
+
+	clone := base.Clone().
+		Edge("friends->groups", dql.Select("name")).
+		OrderAsc("name")
+
+	expectedBase := dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>)) {
+				<name> <friends> { <name> <posts> { <title> } }
+			}
+		}
+	`)
+
+	query, _, err := base.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, expectedBase, query)
+
+	query, _, err = clone.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>),orderasc:<name>) {
+				<name> <friends> { <name> <posts> { <title> } <groups> { <name> } }
+			}
+		}
+	`), query)
+}
+
+// run with -race to detect any shared state between derived builders
+func Test_Query_Concurrent_Derivation(t *testing.T) {
+	base := dql.QueryType("User").
+		Select("name").
+		Filter(dql.Eq{"active": true}).
+		Edge("friends", dql.Select("name"))
+
+	var wg sync.WaitGroup
+	results := make([]string, 20)
+	errs := make([]error, len(results))
+
+	for i := 0; i < len(results); i++ {
+		wg.Add(1)
+
+		go func(index int) {
+			defer wg.Done()
+
+			derived := base.
+				Edge(fmt.Sprintf("edge%d", index), dql.Select("uid")).
+				Edge("friends->posts", dql.Select("title")).
+				Filter(dql.Eq{"index": index}).
+				OrderAsc("name")
+
+			results[index], _, errs[index] = derived.ToDQL()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for index, query := range results {
+		require.NoError(t, errs[index])
+		require.Equal(t, dql.Minify(fmt.Sprintf(`
+			query Rootquery($0:bool, $1:int) {
+				<rootQuery>(func: type(<User>),orderasc:<name>) @filter(eq(<active>,$0) AND eq(<index>,$1)) {
+					<name> <friends> { <name> <posts> { <title> } } <edge%d> { <uid> }
+				}
+			}
+		`, index)), query)
+	}
+
+	query, _, err := base.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:bool) {
+			<rootQuery>(func: type(<User>)) @filter(eq(<active>,$0)) {
+				<name> <friends> { <name> }
+			}
+		}
+	`), query)
+}
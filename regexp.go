@@ -0,0 +1,140 @@
+package dqlx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// minTrigramLength the minimum length of a literal
+// matched by a regular expression on a trigram index
+const minTrigramLength = 3
+
+// SafeRegexp represents a validated regular expression,
+// unlike Regexp the pattern is sent as a GraphQL variable
+// Expression: regexp(predicate, $pattern)
+type SafeRegexp struct {
+	predicate       string
+	pattern         string
+	caseInsensitive bool
+}
+
+// NewRegexp validates the pattern of a regexp expression,
+// the pattern is either a plain expression or the /pattern/ and /pattern/i forms.
+// Delimiters within the pattern are escaped
+//
+// Example:
+//   filter, err := dqlx.NewRegexp("name", "/^Steven.*$/i")
+//   filter, err := dqlx.NewRegexp("path", "^/home/")
+func NewRegexp(predicate string, pattern string) (SafeRegexp, error) {
+	expression := SafeRegexp{predicate: predicate, pattern: pattern}
+
+	if strings.HasPrefix(pattern, "/") {
+		end := strings.LastIndex(pattern, "/")
+
+		if end == 0 {
+			return SafeRegexp{}, fmt.Errorf("regexp on '%s': missing closing delimiter", predicate)
+		}
+
+		switch flags := pattern[end+1:]; flags {
+		case "":
+		case "i":
+			expression.caseInsensitive = true
+		default:
+			return SafeRegexp{}, fmt.Errorf("regexp on '%s': unsupported flags '%s'", predicate, flags)
+		}
+
+		expression.pattern = pattern[1:end]
+	}
+
+	if expression.pattern == "" {
+		return SafeRegexp{}, fmt.Errorf("regexp on '%s': empty pattern", predicate)
+	}
+
+	if _, err := regexp.Compile(expression.pattern); err != nil {
+		return SafeRegexp{}, fmt.Errorf("regexp on '%s': %w", predicate, err)
+	}
+
+	expression.pattern = escapeRegexpDelimiters(expression.pattern)
+
+	return expression, nil
+}
+
+// RegexpContains matches values containing the input,
+// the input is matched literally. Trigram indexes require at least 3 characters
+//
+// Example:
+//   filter, err := dqlx.RegexpContains("name", searchInput)
+func RegexpContains(predicate string, input string) (SafeRegexp, error) {
+	return literalRegexp(predicate, input, "")
+}
+
+// RegexpStartsWith matches values starting with the input,
+// the input is matched literally. Trigram indexes require at least 3 characters
+//
+// Example:
+//   filter, err := dqlx.RegexpStartsWith("name", searchInput)
+func RegexpStartsWith(predicate string, input string) (SafeRegexp, error) {
+	return literalRegexp(predicate, input, "^")
+}
+
+func literalRegexp(predicate string, input string, prefix string) (SafeRegexp, error) {
+	if utf8.RuneCountInString(input) < minTrigramLength {
+		return SafeRegexp{}, fmt.Errorf("regexp on '%s': the input must contain at least %d characters", predicate, minTrigramLength)
+	}
+
+	return NewRegexp(predicate, prefix+regexp.QuoteMeta(input))
+}
+
+// IgnoreCase matches values regardless of their case
+func (expression SafeRegexp) IgnoreCase() SafeRegexp {
+	expression.caseInsensitive = true
+	return expression
+}
+
+// String returns the pattern in the /pattern/ form
+func (expression SafeRegexp) String() string {
+	if expression.caseInsensitive {
+		return "/" + expression.pattern + "/i"
+	}
+
+	return "/" + expression.pattern + "/"
+}
+
+// ToDQL returns the DQL statement for the 'regexp' expression
+func (expression SafeRegexp) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(expression)
+}
+
+func (expression SafeRegexp) renderDQL(writer *dqlWriter) error {
+	if expression.pattern == "" {
+		return fmt.Errorf("regexp on '%s': empty pattern", expression.predicate)
+	}
+
+	return filterExpr{
+		funcType: regexpFunc,
+		value:    filterKV{expression.predicate: expression.String()},
+	}.renderDQL(writer)
+}
+
+// escapeRegexpDelimiters escapes the slashes not already escaped
+func escapeRegexpDelimiters(pattern string) string {
+	var escaped strings.Builder
+	escaping := false
+
+	for _, char := range pattern {
+		switch {
+		case escaping:
+			escaping = false
+		case char == '\\':
+			escaping = true
+		case char == '/':
+			escaped.WriteRune('\\')
+		}
+
+		escaped.WriteRune(char)
+	}
+
+	return escaped.String()
+}
@@ -0,0 +1,84 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeRegexp(t *testing.T) {
+	t.Run("binds the pattern", func(t *testing.T) {
+		filter, err := dql.NewRegexp("name", "/^Steven Sp.*$/i")
+		require.NoError(t, err)
+
+		query, args, err := filter.ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, "regexp(<name>,??)", query)
+		require.Equal(t, []interface{}{"/^Steven Sp.*$/i"}, args)
+	})
+
+	t.Run("plain pattern", func(t *testing.T) {
+		filter, err := dql.NewRegexp("name", "^Steven")
+		require.NoError(t, err)
+		require.Equal(t, "/^Steven/", filter.String())
+		require.Equal(t, "/^Steven/i", filter.IgnoreCase().String())
+	})
+
+	t.Run("escapes delimiters", func(t *testing.T) {
+		filter, err := dql.NewRegexp("path", `^/home/a\/b`)
+		require.NoError(t, err)
+		require.Equal(t, `/^\/home\/a\/b/`, filter.String())
+
+		filter, err = dql.NewRegexp("path", "/a/b/")
+		require.NoError(t, err)
+		require.Equal(t, `/a\/b/`, filter.String())
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		_, err := dql.NewRegexp("name", "/abc")
+		require.EqualError(t, err, "regexp on 'name': missing closing delimiter")
+
+		_, err = dql.NewRegexp("name", "/abc/g")
+		require.EqualError(t, err, "regexp on 'name': unsupported flags 'g'")
+
+		_, err = dql.NewRegexp("name", "//")
+		require.EqualError(t, err, "regexp on 'name': empty pattern")
+
+		_, err = dql.NewRegexp("name", "a(b")
+		require.Error(t, err)
+
+		_, _, err = dql.SafeRegexp{}.ToDQL()
+		require.Error(t, err)
+	})
+
+	t.Run("contains", func(t *testing.T) {
+		filter, err := dql.RegexpContains("name", "a.b/) OR has(password")
+		require.NoError(t, err)
+		require.Equal(t, `/a\.b\/\) OR has\(password/`, filter.String())
+
+		_, err = dql.RegexpContains("name", "ab")
+		require.EqualError(t, err, "regexp on 'name': the input must contain at least 3 characters")
+	})
+
+	t.Run("starts with", func(t *testing.T) {
+		filter, err := dql.RegexpStartsWith("name", "Ste*")
+		require.NoError(t, err)
+		require.Equal(t, `/^Ste\*/i`, filter.IgnoreCase().String())
+	})
+
+	t.Run("query", func(t *testing.T) {
+		filter, err := dql.RegexpContains("name", "ven")
+		require.NoError(t, err)
+
+		query, variables, err := dql.Query(dql.TypeFn("User")).
+			Filter(filter.IgnoreCase()).
+			Select("name").
+			ToDQL()
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"$0": "/ven/i"}, variables)
+		require.Contains(t, query, "@filter(regexp(<name>,$0))")
+		require.Contains(t, query, "$0:string")
+	})
+}
@@ -0,0 +1,113 @@
+package dqlx
+
+// TypeSelectionOptionFn used to modify options of a type selection
+type TypeSelectionOptionFn func(selection *typeSelection)
+
+// WithEdgeDepth selects the edges of the type as nested selections
+// up to the given depth, edges are not selected by default
+func WithEdgeDepth(depth int) TypeSelectionOptionFn {
+	return func(selection *typeSelection) {
+		selection.depth = depth
+	}
+}
+
+// WithoutPasswords excludes password predicates from the selection
+func WithoutPasswords() TypeSelectionOptionFn {
+	return func(selection *typeSelection) {
+		selection.excludePasswords = true
+	}
+}
+
+type typeSelection struct {
+	dGraphType       *dGraphType
+	schema           *SchemaBuilder
+	depth            int
+	excludePasswords bool
+}
+
+// SelectType selects every predicate of a type registered with a schema.
+// Edges pointing to types of the same schema are expanded in place,
+// other edges only select the uid.
+//
+// Example:
+//   user := schema.Type("User", func(user *dqlx.TypeBuilder) { ... })
+//   dqlx.Query(...).Select(user)
+//   dqlx.Query(...).Select(dqlx.SelectType(user, dqlx.WithEdgeDepth(2), dqlx.WithoutPasswords()))
+func SelectType(typeBuilder *TypeBuilder, options ...TypeSelectionOptionFn) DQLizer {
+	selection := typeSelection{
+		dGraphType: typeBuilder.dGraphType,
+		schema:     typeBuilder.schema,
+	}
+
+	for _, option := range options {
+		option(&selection)
+	}
+
+	return nodeAttributes{predicates: []interface{}{selection}}
+}
+
+// ToDQL returns the selection set of the type
+func (selection typeSelection) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(selection)
+}
+
+func (selection typeSelection) renderDQL(writer *dqlWriter) error {
+	return renderSelectedFields(writer, selection.selectedFields())
+}
+
+// selectedFields returns the predicates of the type,
+// edges are expanded up to the depth of the selection
+func (selection typeSelection) selectedFields() []selectedField {
+	return selection.fields(selection.dGraphType, selection.depth)
+}
+
+func (selection typeSelection) fields(dGraphType *dGraphType, depth int) []selectedField {
+	var fields []selectedField
+	selected := map[string]bool{}
+
+	for _, predicate := range dGraphType.predicates {
+		isEdge := !isKnownScalarType(predicate.ScalarType) || predicate.ScalarType == ScalarUID
+
+		switch {
+		case selected[predicate.Name]:
+			continue
+		case predicate.ScalarType == ScalarPassword && selection.excludePasswords:
+			continue
+		case isEdge && depth <= 0:
+			continue
+		}
+
+		selected[predicate.Name] = true
+
+		field := selectedField{
+			predicate: predicate.Name,
+			isEdge:    isEdge,
+		}
+
+		if predicate.Reverse {
+			field.predicate = "~" + predicate.Name
+		}
+
+		if edgeType := selection.lookupType(predicate.ScalarType); isEdge && edgeType != nil {
+			field.fields = selection.fields(edgeType, depth-1)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func (selection typeSelection) lookupType(name DGraphScalar) *dGraphType {
+	if selection.schema == nil {
+		return nil
+	}
+
+	for _, schemaType := range selection.schema.Types {
+		if schemaType.name == string(name) {
+			return schemaType
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func newSelectionSchema() (*dql.SchemaBuilder, *dql.TypeBuilder) {
+	schema := dql.NewSchema()
+
+	user := schema.Type("User", func(user *dql.TypeBuilder) {
+		user.String("name").IndexTerm()
+		user.Int("age")
+		user.Password("password")
+		user.Type("posts", "Post").List()
+		user.UID("avatar")
+	}, dql.WithTypePrefix(false))
+
+	schema.Type("Post", func(post *dql.TypeBuilder) {
+		post.String("title")
+		post.Type("author", "User")
+	}, dql.WithTypePrefix(false))
+
+	return schema, user
+}
+
+func Test_Select_Type(t *testing.T) {
+	_, user := newSelectionSchema()
+
+	query, _, err := dql.QueryType("User").Select(user).ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>)) { <name> <age> <password> }
+		}
+	`), dql.Minify(query))
+}
+
+func Test_Select_Type_Edges(t *testing.T) {
+	_, user := newSelectionSchema()
+
+	query, _, err := dql.QueryType("User").
+		Select(dql.SelectType(user, dql.WithEdgeDepth(2), dql.WithoutPasswords())).
+		ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>)) {
+				<name> <age>
+				<posts> { <title> <author> { <name> <age> } }
+				<avatar> { <uid> }
+			}
+		}
+	`), dql.Minify(query))
+
+	query, _, err = dql.QueryType("Post").
+		Select("uid").
+		Edge("author", dql.SelectType(user, dql.WithEdgeDepth(1))).
+		ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<Post>)) {
+				<uid>
+				<author> { <name> <age> <password> <posts> { <title> } <avatar> { <uid> } }
+			}
+		}
+	`), dql.Minify(query))
+}
+
+func Test_Select_Type_Without_Schema(t *testing.T) {
+	user := dql.NewTypeBuilder("User")
+	user.String("name")
+	user.Type("posts", "Post")
+
+	query, _, err := dql.QueryType("User").Select(dql.SelectType(user, dql.WithEdgeDepth(1))).ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>)) { <User.name> <User.posts> { <uid> } }
+		}
+	`), dql.Minify(query))
+}
+
+func Test_Select_Type_AST(t *testing.T) {
+	_, user := newSelectionSchema()
+
+	document := dql.QueryType("User").Select(dql.SelectType(user, dql.WithEdgeDepth(2))).AST()
+	block := document.Blocks[0]
+
+	require.Len(t, block.Selections, 3)
+	require.Equal(t, "password", block.Selections[2].Predicate)
+
+	require.Len(t, block.Edges, 2)
+	require.Equal(t, "posts", block.Edges[0].Path)
+	require.Equal(t, "posts->author", block.Edges[0].Edges[0].Path)
+	require.Equal(t, "age", block.Edges[0].Edges[0].Selections[1].Predicate)
+	require.Equal(t, "avatar", block.Edges[1].Path)
+	require.Equal(t, "uid", block.Edges[1].Selections[0].Predicate)
+}
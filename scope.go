@@ -0,0 +1,134 @@
+package dqlx
+
+// QueryFn represents a function which receives a query builder
+// and returns a modified copy of it
+type QueryFn func(builder QueryBuilder) QueryBuilder
+
+// Scope represents a reusable and composable portion of a query.
+// Scopes with a Name are applied at most once per builder,
+// anonymous scopes are applied every time they are requested.
+//
+// Example:
+//   var Active = dqlx.NewScope("active", func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//     return builder.Filter(dqlx.Eq{"active": true})
+//   })
+type Scope struct {
+	Name string
+	Fn   QueryFn
+}
+
+// NewScope creates a named scope
+//
+// Example:
+//   func VisibleTo(userID string) dqlx.Scope {
+//     return dqlx.NewScope("visibleTo:"+userID, func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//       return builder.Filter(dqlx.UIDIn{"members": userID})
+//     })
+//   }
+func NewScope(name string, fn QueryFn) Scope {
+	return Scope{
+		Name: name,
+		Fn:   fn,
+	}
+}
+
+// ComposeScopes groups multiple scopes under a single name.
+// The inner scopes are applied in the given order and are de-duplicated
+// against the scopes already applied on the builder.
+//
+// Example:
+//   dqlx.ComposeScopes("publicFeed", Active, Published)
+func ComposeScopes(name string, scopes ...Scope) Scope {
+	return NewScope(name, func(builder QueryBuilder) QueryBuilder {
+		return builder.Scopes(scopes...)
+	})
+}
+
+// Scopes applies the given scopes in order.
+// Named scopes already applied on the builder are skipped.
+//
+// Example:
+//   dqlx.QueryType("Post").Scopes(Active, VisibleTo(userID))
+//   dqlx.Query(...).EdgeFn("posts", func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//     return builder.Scopes(Published)
+//   })
+func (builder QueryBuilder) Scopes(scopes ...Scope) QueryBuilder {
+	for _, scope := range scopes {
+		if scope.Name != "" {
+			if builder.HasScope(scope.Name) {
+				continue
+			}
+			builder.scopes = append(builder.scopes[:len(builder.scopes):len(builder.scopes)], scope.Name)
+		}
+
+		if scope.Fn != nil {
+			builder = scope.Fn(builder)
+		}
+	}
+	return builder
+}
+
+// HasScope determines if a named scope has been applied to the builder
+func (builder QueryBuilder) HasScope(name string) bool {
+	for _, scope := range builder.scopes {
+		if scope == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetScopes returns the names of the applied scopes in the order
+// they were applied
+func (builder QueryBuilder) GetScopes() []string {
+	scopes := make([]string, len(builder.scopes))
+	copy(scopes, builder.scopes)
+	return scopes
+}
+
+// Apply applies the given functions in order
+//
+// Example:
+//   dqlx.Query(...).Apply(withPagination, withOwner)
+func (builder QueryBuilder) Apply(fns ...QueryFn) QueryBuilder {
+	for _, fn := range fns {
+		if fn != nil {
+			builder = fn(builder)
+		}
+	}
+	return builder
+}
+
+// When applies the function only if the condition is true
+//
+// Example:
+//   dqlx.Query(...).When(search != "", func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//     return builder.Filter(dqlx.AllOfTerms{"name": search})
+//   })
+func (builder QueryBuilder) When(condition bool, fn QueryFn) QueryBuilder {
+	if !condition {
+		return builder
+	}
+	return builder.Apply(fn)
+}
+
+// WhenElse applies fn if the condition is true, otherwise elseFn
+//
+// Example:
+//   dqlx.Query(...).WhenElse(asc, byNameAsc, byNameDesc)
+func (builder QueryBuilder) WhenElse(condition bool, fn QueryFn, elseFn QueryFn) QueryBuilder {
+	if condition {
+		return builder.Apply(fn)
+	}
+	return builder.Apply(elseFn)
+}
+
+// Unless applies the function only if the condition is false
+//
+// Example:
+//   dqlx.Query(...).Unless(isAdmin, func(builder dqlx.QueryBuilder) dqlx.QueryBuilder {
+//     return builder.Filter(dqlx.Eq{"published": true})
+//   })
+func (builder QueryBuilder) Unless(condition bool, fn QueryFn) QueryBuilder {
+	return builder.When(!condition, fn)
+}
@@ -0,0 +1,123 @@
+package dqlx_test
+
+import (
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+var activeScope = dql.NewScope("active", func(builder dql.QueryBuilder) dql.QueryBuilder {
+	return builder.Filter(dql.Eq{"active": true})
+})
+
+var publishedScope = dql.NewScope("published", func(builder dql.QueryBuilder) dql.QueryBuilder {
+	return builder.Filter(dql.Has("published_at"))
+})
+
+func visibleTo(user string) dql.Scope {
+	return dql.NewScope("visibleTo:"+user, func(builder dql.QueryBuilder) dql.QueryBuilder {
+		return builder.Filter(dql.Eq{"owner": user})
+	})
+}
+
+func Test_Query_Scopes(t *testing.T) {
+	query, variables, err := dql.
+		QueryType("Post").
+		Select("uid").
+		Scopes(activeScope, visibleTo("alice"), activeScope).
+		EdgeFn("comments", func(builder dql.QueryBuilder) dql.QueryBuilder {
+			return builder.Select("text").Scopes(publishedScope)
+		}).
+		ToDQL()
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"$0": "true",
+		"$1": "alice",
+	}, variables)
+
+	expected := dql.Minify(`
+		query Rootquery($0:bool, $1:string) {
+			<rootQuery>(func: type(<Post>)) @filter(eq(<active>,$0) AND eq(<owner>,$1)) {
+				<uid>
+				<comments> @filter(has(<published_at>)) {
+					<text>
+				}
+			}
+		}
+	`)
+
+	require.Equal(t, expected, query)
+}
+
+func Test_Query_Scopes_Composed(t *testing.T) {
+	feed := dql.ComposeScopes("feed", activeScope, publishedScope)
+
+	builder := dql.QueryType("Post").
+		Scopes(publishedScope, feed, feed)
+
+	require.Equal(t, []string{"published", "feed", "active"}, builder.GetScopes())
+	require.True(t, builder.HasScope("active"))
+	require.False(t, builder.HasScope("visibleTo:alice"))
+
+	query, _, err := builder.ToDQL()
+	require.NoError(t, err)
+
+	expected := "query Rootquery($0:bool) { <rootQuery>(func: type(<Post>)) @filter(has(<published_at>) AND eq(<active>,$0)) {  } }"
+
+	require.Equal(t, expected, query)
+}
+
+func Test_Query_Scopes_Siblings(t *testing.T) {
+	// three scopes leave spare capacity in the applied scopes
+	base := dql.QueryType("Post").Scopes(activeScope, publishedScope, visibleTo("alice"))
+
+	bob := base.Scopes(visibleTo("bob"))
+	carol := base.Scopes(visibleTo("carol"))
+
+	require.Equal(t, []string{"active", "published", "visibleTo:alice", "visibleTo:bob"}, bob.GetScopes())
+	require.Equal(t, []string{"active", "published", "visibleTo:alice", "visibleTo:carol"}, carol.GetScopes())
+	require.False(t, bob.HasScope("visibleTo:carol"))
+	require.Equal(t, []string{"active", "published", "visibleTo:alice"}, base.GetScopes())
+}
+
+func Test_Query_When(t *testing.T) {
+	search := func(term string, admin bool) dql.QueryBuilder {
+		return dql.QueryType("User").
+			Select("name").
+			When(term != "", func(builder dql.QueryBuilder) dql.QueryBuilder {
+				return builder.Filter(dql.AllOfTerms{"name": term})
+			}).
+			Unless(admin, func(builder dql.QueryBuilder) dql.QueryBuilder {
+				return builder.Filter(dql.Eq{"public": true})
+			}).
+			WhenElse(admin, func(builder dql.QueryBuilder) dql.QueryBuilder {
+				return builder.OrderAsc("email")
+			}, func(builder dql.QueryBuilder) dql.QueryBuilder {
+				return builder.OrderAsc("name")
+			})
+	}
+
+	query, variables, err := search("", true).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{}, variables)
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<User>),orderasc:<email>) {
+				<name>
+			}
+		}
+	`), query)
+
+	query, variables, err = search("ali", false).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"$0": "ali", "$1": "true"}, variables)
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:string, $1:bool) {
+			<rootQuery>(func: type(<User>),orderasc:<name>) @filter(allofterms(<name>,$0) AND eq(<public>,$1)) {
+				<name>
+			}
+		}
+	`), query)
+}
@@ -1,7 +1,6 @@
 package dqlx
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 )
@@ -15,11 +14,13 @@ type node struct {
 
 // ToDQL returns the DQL statements for representing a selection set
 func (node node) ToDQL() (query string, args []interface{}, err error) {
-	writer := bytes.Buffer{}
+	return renderToDQL(node)
+}
 
+func (node node) renderDQL(writer *dqlWriter) error {
 	if node.Attributes != nil {
-		if err := addPart(node.Attributes, &writer, &args); err != nil {
-			return "", nil, err
+		if err := writer.writePart(node.Attributes); err != nil {
+			return err
 		}
 	}
 
@@ -27,20 +28,7 @@ func (node node) ToDQL() (query string, args []interface{}, err error) {
 	nestedEdges, ok := node.Edges[node.ParentName]
 
 	if !ok {
-		return writer.String(), args, nil
-	}
-
-	statements := make([]string, 0, len(nestedEdges))
-
-	for _, queryBuilder := range nestedEdges {
-		nestedEdge, edgesArgs, err := queryBuilder.rootEdge.ToDQL()
-		args = append(args, edgesArgs...)
-
-		if err != nil {
-			return "", nil, err
-		}
-
-		statements = append(statements, nestedEdge)
+		return nil
 	}
 
 	// add a space if parent nodeAttributes are present
@@ -48,18 +36,56 @@ func (node node) ToDQL() (query string, args []interface{}, err error) {
 		writer.WriteString(" ")
 	}
 
-	writer.WriteString(strings.Join(statements, " "))
+	for index, queryBuilder := range nestedEdges {
+		// nested edges might hold an older copy of the edges map,
+		// the one of the parent is always the most recent
+		nestedEdgeRoot := queryBuilder.rootEdge
+		nestedEdgeRoot.Node.Edges = node.Edges
+
+		if index > 0 {
+			writer.WriteString(" ")
+		}
+
+		if err := nestedEdgeRoot.renderDQL(writer); err != nil {
+			return err
+		}
+	}
 
-	return writer.String(), args, nil
+	return nil
 }
 
 type nodeAttributes struct {
 	predicates []interface{}
 }
 
-// Select adds nodeAttributes to selection set
+// Select adds nodeAttributes to selection set,
+// struct values and types select the predicates named by their tags,
+// schema types select all their predicates
+//
+// Example:
+//   dqlx.Select("name", "age")
+//   dqlx.Select(User{})
+//   dqlx.Select(schema.Type("User", ...))
 func Select(predicates ...interface{}) DQLizer {
-	return nodeAttributes{predicates}
+	selected := make([]interface{}, len(predicates))
+
+	for index, predicate := range predicates {
+		selected[index] = predicate
+
+		switch cast := predicate.(type) {
+		case DQLizer:
+			continue
+		case *TypeBuilder:
+			selected[index] = SelectType(cast)
+			continue
+		}
+
+		if selection, ok := newStructSelection(predicate); ok {
+			selected[index] = selection
+		}
+	}
+
+	return nodeAttributes{selected}
 }
 
 // Fields alias of Select
@@ -70,28 +96,102 @@ func Fields(predicates ...interface{}) DQLizer {
 
 // ToDQL returns the dql statement for selected nodeAttributes
 func (fields nodeAttributes) ToDQL() (query string, args []interface{}, err error) {
-	var selectedFields []string
+	return renderToDQL(fields)
+}
+
+func (fields nodeAttributes) renderDQL(writer *dqlWriter) error {
+	separate := false
 
 	for _, field := range fields.predicates {
 		switch requestField := field.(type) {
 		case DQLizer:
-			computedDql, computedArgs, err := requestField.ToDQL()
+			if separate {
+				writer.WriteString(" ")
+			}
 
-			if err != nil {
-				return "", nil, err
+			if err := writer.writePart(requestField); err != nil {
+				return err
 			}
 
-			args = append(args, computedArgs...)
-			selectedFields = append(selectedFields, computedDql)
+			separate = true
 		case string:
-			fieldString := parsePredicates(requestField)
-			selectedFields = append(selectedFields, fieldString...)
+			for _, predicate := range parsePredicates(requestField) {
+				if separate {
+					writer.WriteString(" ")
+				}
+
+				writer.WriteString(predicate)
+				separate = true
+			}
 		default:
-			return "", nil, fmt.Errorf("nodeAttributes can only accept strings or Dqlizer, given %v", requestField)
+			return fmt.Errorf("nodeAttributes can only accept strings or Dqlizer, given %v", requestField)
 		}
 	}
 
-	return strings.Join(selectedFields, " "), args, nil
+	return nil
+}
+
+// selectedField a predicate selected by a struct or a schema type,
+// edges select their nested fields or the uid
+type selectedField struct {
+	predicate string
+	alias     string
+	facets    []interface{}
+	isEdge    bool
+	fields    []selectedField
+}
+
+// fieldsSelection selections expanding into predicates and edges,
+// the AST holds the expanded selection
+type fieldsSelection interface {
+	DQLizer
+	selectedFields() []selectedField
+}
+
+func (field selectedField) renderDQL(writer *dqlWriter) error {
+	if field.alias != "" {
+		writer.WriteString(EscapePredicate(field.alias))
+		writer.WriteString(":")
+	}
+
+	writer.WriteString(EscapePredicate(field.predicate))
+
+	if len(field.facets) > 0 {
+		writer.WriteString(" ")
+
+		if err := writer.writePart(Facets(field.facets...)); err != nil {
+			return err
+		}
+	}
+
+	if !field.isEdge {
+		return nil
+	}
+
+	writer.WriteString(" { ")
+
+	if len(field.fields) == 0 {
+		writer.WriteString(EscapePredicate("uid"))
+	} else if err := renderSelectedFields(writer, field.fields); err != nil {
+		return err
+	}
+
+	writer.WriteString(" }")
+	return nil
+}
+
+func renderSelectedFields(writer *dqlWriter, fields []selectedField) error {
+	for index, field := range fields {
+		if index > 0 {
+			writer.WriteString(" ")
+		}
+
+		if err := field.renderDQL(writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type aliasField struct {
@@ -109,24 +209,22 @@ func Alias(alias string, predicate interface{}) DQLizer {
 
 // ToDQL returns the alias dql statement of a field
 func (aliasField aliasField) ToDQL() (query string, args []interface{}, err error) {
-	var value string
+	return renderToDQL(aliasField)
+}
+
+func (aliasField aliasField) renderDQL(writer *dqlWriter) error {
+	writer.WriteString(EscapePredicate(aliasField.alias))
+	writer.WriteString(":")
 
 	switch cast := aliasField.value.(type) {
 	case DQLizer:
-		value, args, err = cast.ToDQL()
-
-		if err != nil {
-			return "", nil, err
-		}
+		return writer.writePart(cast)
 	case string:
-		value = EscapePredicate(cast)
-	default:
-		return "", nil, fmt.Errorf("alias only accepts  string or DQlizers, given %v", value)
+		writer.WriteString(EscapePredicate(cast))
+		return nil
 	}
 
-	aliasName := EscapePredicate(aliasField.alias)
-
-	return fmt.Sprintf("%s:%s", aliasName, value), args, nil
+	return fmt.Errorf("alias only accepts  string or DQlizers, given %v", aliasField.value)
 }
 
 type as struct {
@@ -145,24 +243,22 @@ func As(varName string, predicate interface{}) DQLizer {
 
 // ToDQL returns the dql statement for a field variable
 func (as as) ToDQL() (query string, args []interface{}, err error) {
-	var predicate string
+	return renderToDQL(as)
+}
+
+func (as as) renderDQL(writer *dqlWriter) error {
+	writer.WriteString(escapeSpecialChars(as.variable))
+	writer.WriteString(" as ")
 
 	switch cast := as.predicate.(type) {
 	case DQLizer:
-		predicate, args, err = cast.ToDQL()
-
-		if err != nil {
-			return "", nil, err
-		}
+		return writer.writePart(cast)
 	case string:
-		predicate = EscapePredicate(cast)
-	default:
-		return "", nil, fmt.Errorf("alias only accepts  string or DQlizers, given %v", predicate)
+		writer.WriteString(EscapePredicate(cast))
+		return nil
 	}
 
-	varName := escapeSpecialChars(as.variable)
-
-	return fmt.Sprintf("%s as %s", varName, predicate), args, nil
+	return fmt.Errorf("alias only accepts  string or DQlizers, given %v", as.predicate)
 }
 
 func parsePredicates(predicates string) []string {
@@ -1,25 +1,28 @@
 package dqlx
 
 import (
-	"bytes"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
-func toVariables(rawVariables map[int]interface{}) (variables map[string]string, placeholders []string) {
-	variables = map[string]string{}
-	placeholders = make([]string, len(rawVariables))
+var uidPattern = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
 
-	queryPlaceholderNames := getSortedVariables(rawVariables)
+// toVariables returns the GraphQL variables of the arguments,
+// and their declarations
+func toVariables(args []interface{}) (variables map[string]string, placeholders []string) {
+	variables = make(map[string]string, len(args))
+	placeholders = make([]string, len(args))
 
-	// Format Variables
-	for index, placeholderName := range queryPlaceholderNames {
-		variableName := fmt.Sprintf("$%d", placeholderName)
+	for index, arg := range args {
+		variableName := "$" + strconv.Itoa(index)
 
-		variables[variableName] = toVariableValue(rawVariables[index])
-		placeholders[index] = fmt.Sprintf("$%d:%s", placeholderName, goTypeToDQLType(rawVariables[placeholderName]))
+		variables[variableName] = toVariableValue(arg)
+		placeholders[index] = variableName + ":" + goTypeToDQLType(arg)
 	}
 
 	return variables, placeholders
@@ -36,8 +39,50 @@ func toVariableValue(value interface{}) string {
 	}
 }
 
+// toConditionLiteral formats a value as a literal of a mutation condition,
+// strings are quoted and values that can't be represented safely are rejected
+func toConditionLiteral(value interface{}) (string, error) {
+	switch val := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case time.Time:
+		return quoteConditionString(val.Format(time.RFC3339))
+	case *time.Time:
+		if val != nil {
+			return quoteConditionString(val.Format(time.RFC3339))
+		}
+	case string:
+		if uidPattern.MatchString(val) {
+			return val, nil
+		}
+
+		return quoteConditionString(val)
+	}
+
+	return "", fmt.Errorf("unsupported value of type %T in a mutation condition", value)
+}
+
+func quoteConditionString(value string) (string, error) {
+	for _, char := range value {
+		if unicode.IsControl(char) {
+			return "", fmt.Errorf("control characters are not allowed in a mutation condition: %q", value)
+		}
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`, nil
+}
+
 func goTypeToDQLType(value interface{}) string {
-	switch value.(type) {
+	switch cast := value.(type) {
+	case Parameter:
+		return cast.variableType()
 	case string:
 		return "string"
 	case int, int8, int32, int64:
@@ -70,32 +115,6 @@ func dqlTypeToGoType(value DGraphScalar) string {
 	return string(value)
 }
 
-func replacePlaceholders(query string, args []interface{}, transform func(index int, value interface{}) string) (string, map[int]interface{}) {
-	variables := map[int]interface{}{}
-	buf := &bytes.Buffer{}
-	i := 0
-
-	for {
-		p := strings.Index(query, "??")
-		if p == -1 {
-			break
-		}
-
-		buf.WriteString(query[:p])
-		key := transform(i, args[i])
-		buf.WriteString(key)
-		query = query[p+2:]
-
-		// Assign the variables
-		variables[i] = args[i]
-
-		i++
-	}
-
-	buf.WriteString(query)
-	return buf.String(), variables
-}
-
 func isListType(val interface{}) bool {
 	valVal := reflect.ValueOf(val)
 	return valVal.Kind() == reflect.Array || valVal.Kind() == reflect.Slice
@@ -115,16 +134,3 @@ func addStatement(parts []DQLizer, statements *[]string, args *[]interface{}) er
 
 	return nil
 }
-
-func addPart(part DQLizer, writer *bytes.Buffer, args *[]interface{}) error {
-	statement, statementArgs, err := part.ToDQL()
-	*args = append(*args, statementArgs...)
-
-	if err != nil {
-		return err
-	}
-
-	writer.WriteString(statement)
-
-	return nil
-}
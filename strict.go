@@ -0,0 +1,254 @@
+package dqlx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Strict mode rejects invalid names instead of escaping them.
+//
+// By default predicates, aliases and variables are sanitised while rendering:
+// the characters ^ } | { \ , < > " are removed and a malformed "as" keyword is dropped.
+// Selections with more than one "as" keyword are rejected in strict mode.
+// In strict mode ToDQL returns an *InvalidNameError instead, so that injection
+// attempts can be detected and logged.
+//
+// Grammar of the accepted names:
+//   predicate = [ "~" ] name
+//   name      = ( letter | digit | "_" ) { letter | digit | "_" | "." | "-" }
+//   alias     = name
+//   variable  = ( letter | "_" ) { letter | digit | "_" }
+//   type      = name
+//   language  = "@" ( "*" | tag { ":" tag } )
+//   tag       = ( letter | digit | "-" | "." ) { letter | digit | "-" | "." }
+//   selection = predicate [ language | facets ] | "expand(" ( "_all_" | name ) ")"
+//   facets    = "@facets" [ "(" name { "," name } ")" ]
+//
+// Predicates of filters and orderings may carry a language, as in name@en.
+// Values of functions are always sent as GraphQL variables and are not affected.
+var strictModeEnabled int32
+
+var (
+	strictPredicatePattern = regexp.MustCompile(`^~?[\p{L}\p{N}_][\p{L}\p{N}_.\-]*$`)
+	strictAliasPattern     = regexp.MustCompile(`^[\p{L}\p{N}_][\p{L}\p{N}_.\-]*$`)
+	strictVariablePattern  = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_]*$`)
+	strictLanguagePattern  = regexp.MustCompile(`^@(\*|[\p{L}\p{N}.\-]+(:[\p{L}\p{N}.\-]+)*)$`)
+	strictExpandPattern    = regexp.MustCompile(`^expand\((_all_|[\p{L}\p{N}_][\p{L}\p{N}_.\-]*)\)$`)
+	strictFacetsPattern    = regexp.MustCompile(`^@facets(\([\p{L}\p{N}_.\-]+(,[\p{L}\p{N}_.\-]+)*\))?$`)
+)
+
+type strictness uint8
+
+const (
+	strictInherit strictness = iota
+	strictEnabled
+	strictDisabled
+)
+
+// SetStrictMode enables or disables strict mode for every query builder
+// that doesn't set its own mode
+func SetStrictMode(enabled bool) {
+	var value int32
+
+	if enabled {
+		value = 1
+	}
+
+	atomic.StoreInt32(&strictModeEnabled, value)
+}
+
+// IsStrictMode determines if strict mode is enabled globally
+func IsStrictMode() bool {
+	return atomic.LoadInt32(&strictModeEnabled) == 1
+}
+
+// Strict enables or disables strict mode for this query,
+// overriding the global mode
+//
+// Example:
+//   dqlx.Query(...).Strict(true).Select(userInput).ToDQL() // -> *InvalidNameError
+func (builder QueryBuilder) Strict(enabled bool) QueryBuilder {
+	builder.strict = strictDisabled
+
+	if enabled {
+		builder.strict = strictEnabled
+	}
+
+	return builder
+}
+
+func (builder QueryBuilder) isStrict() bool {
+	switch builder.strict {
+	case strictEnabled:
+		return true
+	case strictDisabled:
+		return false
+	}
+
+	return IsStrictMode()
+}
+
+// InvalidNameError is returned in strict mode when a name
+// doesn't follow the accepted grammar
+type InvalidNameError struct {
+	// Kind of the name: predicate, alias, variable, type or language
+	Kind string
+	Name string
+	// Path of the edge holding the name
+	Path string
+}
+
+// Error returns the error message
+func (err *InvalidNameError) Error() string {
+	return fmt.Sprintf("invalid %s '%s' in %s", err.Kind, err.Name, err.Path)
+}
+
+// ValidateNames validates the names used by the queries
+// against the strict mode grammar, regardless of the mode
+//
+// Example:
+//   if err := dqlx.ValidateNames(query); err != nil {
+//     log.Printf("rejected query: %v", err)
+//   }
+func ValidateNames(queries ...QueryBuilder) error {
+	var validationErr error
+	var block, path string
+
+	// nested edges are visited after every other node of their parent,
+	// the last visited edge is the one holding the node
+	Inspect(QueriesToAST(queries...), func(node Node) bool {
+		if validationErr != nil {
+			return false
+		}
+
+		if edgeNode, ok := node.(*EdgeNode); ok {
+			if edgeNode.Path == "" {
+				block = edgeNode.Name
+				path = block
+			} else {
+				path = EdgePath(block, edgeNode.Path)
+			}
+		}
+
+		validationErr = validateNode(node, path)
+		return validationErr == nil
+	})
+
+	return validationErr
+}
+
+func validateStrictQueries(queries []QueryBuilder) error {
+	for _, query := range queries {
+		if !query.isStrict() {
+			continue
+		}
+
+		if err := ValidateNames(query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateNode(node Node, path string) error {
+	checker := nameChecker{path: path}
+
+	switch cast := node.(type) {
+	case *EdgeNode:
+		if cast.Path == "" {
+			checker.check("alias", cast.Name, strictAliasPattern)
+		} else if !strictExpandPattern.MatchString(cast.Name) {
+			checker.check("predicate", cast.Name, strictPredicatePattern)
+		}
+
+		if cast.Alias != "" {
+			checker.check("variable", cast.Alias, strictVariablePattern)
+		}
+
+		for _, predicate := range cast.GroupBy {
+			checker.checkPredicate(predicate)
+		}
+
+		if cast.Cascade != nil {
+			for _, field := range cast.Cascade.Fields {
+				checker.checkPredicate(field)
+			}
+		}
+	case *SelectionNode:
+		if cast.Alias != "" {
+			checker.check("alias", cast.Alias, strictAliasPattern)
+		}
+
+		if cast.Variable != "" {
+			checker.check("variable", cast.Variable, strictVariablePattern)
+		}
+
+		if cast.Expression == nil && !strictExpandPattern.MatchString(cast.Predicate) {
+			checker.check("predicate", cast.Predicate, strictPredicatePattern)
+		}
+
+		if cast.Directive != "" && !strictFacetsPattern.MatchString(cast.Directive) {
+			checker.check("language", cast.Directive, strictLanguagePattern)
+		}
+	case *FilterNode:
+		switch {
+		case cast.Func == string(typeFunc):
+			for _, arg := range cast.Args {
+				if name, ok := arg.(string); ok {
+					checker.check("type", name, strictAliasPattern)
+				}
+			}
+		case cast.Predicate == "":
+		case isVariableFunc(cast.Func):
+			checker.check("variable", cast.Predicate, strictVariablePattern)
+		default:
+			checker.checkPredicate(cast.Predicate)
+		}
+	case *OrderNode:
+		if cast.Predicate != "" {
+			checker.checkPredicate(cast.Predicate)
+		}
+	case *FacetsNode:
+		for _, facet := range cast.Predicates {
+			predicate, alias, _ := parsePredicate(facet)
+
+			if alias != "" {
+				checker.check("alias", alias, strictAliasPattern)
+			}
+
+			checker.check("predicate", strings.TrimSpace(predicate), strictPredicatePattern)
+		}
+	}
+
+	return checker.err
+}
+
+type nameChecker struct {
+	path string
+	err  error
+}
+
+func (checker *nameChecker) check(kind string, name string, pattern *regexp.Regexp) {
+	if checker.err != nil || pattern.MatchString(name) {
+		return
+	}
+
+	checker.err = &InvalidNameError{
+		Kind: kind,
+		Name: name,
+		Path: checker.path,
+	}
+}
+
+// checkPredicate checks a predicate optionally followed by a language
+func (checker *nameChecker) checkPredicate(predicate string) {
+	parts := strings.SplitN(predicate, "@", 2)
+	checker.check("predicate", parts[0], strictPredicatePattern)
+
+	if len(parts) == 2 {
+		checker.check("language", "@"+parts[1], strictLanguagePattern)
+	}
+}
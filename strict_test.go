@@ -0,0 +1,136 @@
+package dqlx_test
+
+import (
+	"errors"
+	"testing"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Strict_Mode_Accepts_Valid_Names(t *testing.T) {
+	query := dql.QueryEdge("bladerunner", dql.EqFn("name@en", "Blade Runner")).
+		Strict(true).
+		Select(`
+			uid
+			D as name@en
+			alias:name@en:fr
+			dgraph.type
+			expand(_all_)
+			friends @facets(since)
+		`, dql.Alias("total", dql.Count("films"))).
+		Filter(dql.Or{dql.Eq{"~director.film": "x"}, dql.Has("email")}).
+		OrderAsc("name@.").
+		GroupBy("genre").
+		Cascade("name").
+		Facets("since").
+		EdgeAs("F", "films", dql.Select("title"), dql.UID(dql.Val("D")))
+
+	_, _, err := query.ToDQL()
+	require.NoError(t, err)
+}
+
+func Test_Strict_Mode_Rejects_Invalid_Names(t *testing.T) {
+	cases := map[string]struct {
+		query    dql.QueryBuilder
+		expected string
+	}{
+		"predicate": {
+			query:    dql.QueryType("User").Select("name } secret {"),
+			expected: "invalid predicate 'name } secret {' in rootQuery",
+		},
+		"malformed as": {
+			query:    dql.QueryType("User").Select("D xs name"),
+			expected: "invalid predicate 'D xs name' in rootQuery",
+		},
+		"repeated as": {
+			query:    dql.QueryType("User").Select("x as y as z"),
+			expected: "invalid predicate 'y as z' in rootQuery",
+		},
+		"has with as": {
+			query:    dql.QueryType("User").Filter(dql.Has("x as y as z")),
+			expected: "invalid predicate 'x as y as z' in rootQuery",
+		},
+		"has": {
+			query:    dql.QueryType("User").Filter(dql.Has("name } evil(func: has(x)) { uid")),
+			expected: "invalid predicate 'name } evil(func: has(x)) { uid' in rootQuery",
+		},
+		"has with a list": {
+			query:    dql.QueryType("User").Filter(dql.Has("name,email")),
+			expected: "invalid predicate 'name,email' in rootQuery",
+		},
+		"type": {
+			query:    dql.Query(dql.TypeFn("User } x(func: has(p)) {")).Select("uid"),
+			expected: "invalid type 'User } x(func: has(p)) {' in rootQuery",
+		},
+		"val": {
+			query:    dql.QueryType("User").Filter(dql.UID(dql.Val("x as y"))),
+			expected: "invalid variable 'x as y' in rootQuery",
+		},
+		"alias": {
+			query:    dql.QueryType("User").Select(dql.Alias("a<b", "name")),
+			expected: "invalid alias 'a<b' in rootQuery",
+		},
+		"variable": {
+			query:    dql.QueryType("User").Select(dql.As("D,E", "name")),
+			expected: "invalid variable 'D,E' in rootQuery",
+		},
+		"language": {
+			query:    dql.QueryType("User").Select("name@en\""),
+			expected: "invalid language '@en\"' in rootQuery",
+		},
+		"filter": {
+			query:    dql.QueryType("User").Filter(dql.Eq{"age) OR has(password": 1}),
+			expected: "invalid predicate 'age) OR has(password' in rootQuery",
+		},
+		"nested edge": {
+			query: dql.QueryType("User").
+				Edge("friends", dql.Select("name")).
+				Edge("friends->posts", dql.Select("title"), dql.OrderDesc("created_at|x")),
+			expected: "invalid predicate 'created_at|x' in rootQuery->friends->posts",
+		},
+		"edge": {
+			query:    dql.QueryType("User").Edge("friends{"),
+			expected: "invalid predicate 'friends{' in rootQuery->friends{",
+		},
+		"variable block": {
+			query:    dql.QueryType("User").Variable(dql.Variable(dql.TypeFn("User")).Select(dql.As("C D", "uid"))),
+			expected: "invalid variable 'C D' in rootQuery",
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			// names are escaped when strict mode is disabled
+			_, _, err := testCase.query.ToDQL()
+			require.NoError(t, err)
+
+			_, _, err = testCase.query.Strict(true).ToDQL()
+
+			var nameError *dql.InvalidNameError
+			require.True(t, errors.As(err, &nameError))
+			require.EqualError(t, err, testCase.expected)
+
+			require.EqualError(t, dql.ValidateNames(testCase.query), testCase.expected)
+		})
+	}
+}
+
+func Test_Strict_Mode_Global(t *testing.T) {
+	dql.SetStrictMode(true)
+	defer dql.SetStrictMode(false)
+
+	require.True(t, dql.IsStrictMode())
+
+	query := dql.QueryType("User").Select("name>")
+
+	_, _, err := query.ToDQL()
+	require.EqualError(t, err, "invalid predicate 'name>' in rootQuery")
+
+	// builders can opt out
+	_, _, err = query.Strict(false).ToDQL()
+	require.NoError(t, err)
+
+	_, err = dql.Mutation().Query(query).Set(map[string]string{"a": "b"}).Execute(nil)
+	require.Error(t, err)
+}
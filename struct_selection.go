@@ -0,0 +1,215 @@
+package dqlx
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structSelection derives a selection set from the tags of a struct type.
+//
+// The json tag names the key of the response, the dql tag names the predicate
+// when it differs from the key, in which case the predicate is aliased:
+//   Name     string    `json:"name"`                  -> name
+//   Title    string    `json:"title" dql:"name@en"`   -> title:name@en
+//   Since    time.Time `json:"friends|since"`         -> friends @facets(since)
+//   Friends  []User    `json:"friends"`               -> friends { ... }
+//
+// Nested structs, pointers and slices of structs are selected as edges,
+// edges leading back to a struct being selected only select the uid.
+type structSelection struct {
+	structType reflect.Type
+}
+
+// newStructSelection returns the selection of a struct value or type,
+// pointers, slices and arrays are resolved to their element type
+func newStructSelection(value interface{}) (structSelection, bool) {
+	var valueType reflect.Type
+
+	switch cast := value.(type) {
+	case nil:
+		return structSelection{}, false
+	case reflect.Type:
+		valueType = cast
+	default:
+		valueType = reflect.TypeOf(value)
+	}
+
+	structType, ok := selectableStruct(valueType, true)
+
+	if !ok {
+		return structSelection{}, false
+	}
+
+	return structSelection{structType: structType}, true
+}
+
+// selectableStruct resolves the struct type selected by the given type
+func selectableStruct(valueType reflect.Type, allowLists bool) (reflect.Type, bool) {
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	if allowLists && (valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array) {
+		return selectableStruct(valueType.Elem(), false)
+	}
+
+	if valueType.Kind() != reflect.Struct || valueType == timeType {
+		return nil, false
+	}
+
+	return valueType, true
+}
+
+// ToDQL returns the DQL selection set of the struct
+func (selection structSelection) ToDQL() (query string, args []interface{}, err error) {
+	return renderToDQL(selection)
+}
+
+func (selection structSelection) renderDQL(writer *dqlWriter) error {
+	return renderSelectedFields(writer, selection.selectedFields())
+}
+
+// selectedFields returns the fields selected by the struct,
+// structs without selectable fields select the uid
+func (selection structSelection) selectedFields() []selectedField {
+	fields, _ := structSelectedFields(selection.structType, "", map[reflect.Type]bool{})
+
+	if len(fields) == 0 {
+		return []selectedField{{predicate: "uid"}}
+	}
+
+	return fields
+}
+
+type structField struct {
+	key       string
+	predicate string
+	edge      reflect.Type
+	facets    []interface{}
+}
+
+// structSelectedFields returns the fields selected by the struct
+// and the facets requested on the parent edge
+func structSelectedFields(structType reflect.Type, parentKey string, visiting map[reflect.Type]bool) (selected []selectedField, parentFacets []interface{}) {
+	visiting[structType] = true
+	defer delete(visiting, structType)
+
+	fields, parentFacets := structFields(structType, parentKey)
+
+	for _, field := range fields {
+		selectedField := selectedField{
+			predicate: field.predicate,
+			facets:    field.facets,
+			isEdge:    field.edge != nil,
+		}
+
+		if field.key != field.predicate {
+			selectedField.alias = field.key
+		}
+
+		// edges leading back to a struct being selected only select the uid
+		if field.edge != nil && !visiting[field.edge] {
+			nested, edgeFacets := structSelectedFields(field.edge, field.key, visiting)
+
+			selectedField.fields = nested
+			selectedField.facets = append(append([]interface{}{}, field.facets...), edgeFacets...)
+		}
+
+		selected = append(selected, selectedField)
+	}
+
+	return selected, parentFacets
+}
+
+// structFields returns the selected fields of the struct in declaration order,
+// embedded structs are flattened as encoding/json does
+func structFields(structType reflect.Type, parentKey string) (fields []structField, parentFacets []interface{}) {
+	facets := map[string][]interface{}{}
+
+	var collect func(structType reflect.Type)
+	collect = func(structType reflect.Type) {
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			key, predicate, skip := structFieldTags(field)
+
+			if skip {
+				continue
+			}
+
+			if field.Anonymous && key == "" {
+				if embedded, ok := selectableStruct(field.Type, false); ok {
+					collect(embedded)
+					continue
+				}
+			}
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if key == "" {
+				key = field.Name
+			}
+
+			if predicate == "" {
+				predicate = key
+			}
+
+			if facetParts := strings.SplitN(key, "|", 2); len(facetParts) == 2 {
+				if facetParts[0] == parentKey {
+					parentFacets = append(parentFacets, facetParts[1])
+				} else {
+					facets[facetParts[0]] = append(facets[facetParts[0]], facetParts[1])
+				}
+				continue
+			}
+
+			structField := structField{
+				key:       key,
+				predicate: predicate,
+			}
+
+			if edge, ok := selectableStruct(field.Type, true); ok {
+				structField.edge = edge
+			}
+
+			fields = append(fields, structField)
+		}
+	}
+
+	collect(structType)
+
+	for index := range fields {
+		fields[index].facets = facets[fields[index].key]
+	}
+
+	return fields, parentFacets
+}
+
+// structFieldTags returns the response key and the predicate of a field
+func structFieldTags(field reflect.StructField) (key string, predicate string, skip bool) {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		key = strings.Split(tag, ",")[0]
+
+		if key == "-" {
+			return "", "", true
+		}
+	}
+
+	if tag, ok := field.Tag.Lookup("dql"); ok {
+		predicate = strings.Split(tag, ",")[0]
+
+		if predicate == "-" {
+			return "", "", true
+		}
+
+		if key == "" {
+			key = predicate
+		}
+	}
+
+	return key, predicate, false
+}
@@ -0,0 +1,155 @@
+package dqlx_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+type selectedNode struct {
+	UID string `json:"uid,omitempty"`
+}
+
+type selectedFilm struct {
+	selectedNode
+	Name     string           `json:"name@en"`
+	Title    string           `json:"title" dql:"name@it"`
+	Released time.Time        `json:"initial_release_date"`
+	Genres   []selectedGenre  `json:"genre"`
+	Director *selectedPerson  `json:"director"`
+	Actors   []selectedPerson `json:"starring"`
+	Ignored  string           `json:"-"`
+	internal string
+}
+
+type selectedGenre struct {
+	Name  string  `dql:"name"`
+	Score float64 `json:"genre|score"`
+}
+
+type selectedPerson struct {
+	Name       string           `json:"name"`
+	NameSince  time.Time        `json:"name|since"`
+	NameSource string           `json:"name|source"`
+	Films      []selectedFilm   `json:"films"`
+	Friends    []selectedPerson `json:"friends"`
+}
+
+func Test_Select_Struct(t *testing.T) {
+	var films []selectedFilm
+
+	query, _, err := dql.QueryType("Film").UnmarshalInto(&films).ToDQL()
+	require.NoError(t, err)
+
+	expected := dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<Film>)) {
+				<uid>
+				<name>@en
+				<title>:<name>@it
+				<initial_release_date>
+				<genre> @facets(<score>) { <name> }
+				<director> { <name> @facets(<since>,<source>) <films> { <uid> } <friends> { <uid> } }
+				<starring> { <name> @facets(<since>,<source>) <films> { <uid> } <friends> { <uid> } }
+			}
+		}
+	`)
+
+	require.Equal(t, expected, dql.Minify(query))
+}
+
+func Test_Select_Struct_Forms(t *testing.T) {
+	expected, _, err := dql.QueryType("Genre").Select(selectedGenre{}).ToDQL()
+	require.NoError(t, err)
+
+	forms := []interface{}{
+		&selectedGenre{},
+		[]selectedGenre{},
+		&[]*selectedGenre{},
+		reflect.TypeOf(selectedGenre{}),
+	}
+
+	for _, form := range forms {
+		query, _, err := dql.QueryType("Genre").Select(form).ToDQL()
+		require.NoError(t, err)
+		require.Equal(t, expected, query)
+	}
+
+	// selections and edges can be combined with struct selections
+	query, _, err := dql.QueryType("Film").
+		Select(selectedNode{}, "name").
+		Edge("genre", dql.Select(selectedGenre{}), dql.Cursor{First: 5}).
+		ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery($0:int) {
+			<rootQuery>(func: type(<Film>)) {
+				<uid> <name>
+				<genre>(first:$0) { <name> }
+			}
+		}
+	`), dql.Minify(query))
+}
+
+func Test_Unmarshal_Into_Keeps_Selection(t *testing.T) {
+	var films []selectedFilm
+
+	query, _, err := dql.QueryType("Film").Select("name").UnmarshalInto(&films).ToDQL()
+	require.NoError(t, err)
+
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<Film>)) { <name> }
+		}
+	`), dql.Minify(query))
+
+	var result []map[string]interface{}
+
+	query, _, err = dql.QueryType("Film").UnmarshalInto(&result).ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, dql.Minify(`
+		query Rootquery() {
+			<rootQuery>(func: type(<Film>)) {  }
+		}
+	`), dql.Minify(query))
+}
+
+func Test_Select_Struct_AST(t *testing.T) {
+	document := dql.QueryType("Genre").Select(selectedPerson{}).AST()
+	block := document.Blocks[0]
+
+	require.Len(t, block.Selections, 1)
+	require.Equal(t, "name", block.Selections[0].Predicate)
+	require.Equal(t, "@facets(<since>,<source>)", block.Selections[0].Directive)
+	require.Nil(t, block.Selections[0].Expression)
+
+	require.Len(t, block.Edges, 2)
+	require.Equal(t, "films", block.Edges[0].Path)
+	require.Equal(t, "friends", block.Edges[1].Path)
+	require.Equal(t, "uid", block.Edges[1].Selections[0].Predicate)
+
+	films := block.Edges[0]
+	require.Equal(t, []string{"uid", "name", "name"}, []string{
+		films.Selections[0].Predicate,
+		films.Selections[1].Predicate,
+		films.Selections[2].Predicate,
+	})
+	require.Equal(t, "title", films.Selections[2].Alias)
+	require.Equal(t, "@it", films.Selections[2].Directive)
+
+	var paths []string
+
+	dql.Inspect(films, func(node dql.Node) bool {
+		if edge, ok := node.(*dql.EdgeNode); ok {
+			paths = append(paths, edge.Path)
+		}
+		return true
+	})
+
+	require.Equal(t, []string{"films", "films->genre", "films->director", "films->starring"}, paths)
+	require.Equal(t, []string{"score"}, films.Edges[0].Facets[0].Predicates)
+}
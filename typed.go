@@ -0,0 +1,150 @@
+package dqlx
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoResults is returned by QueryOne when the result set is empty
+var ErrNoResults = errors.New("the query returned no results")
+
+// QueryAll executes the query and decodes the result set into a slice of T.
+// When nothing is selected the selection set is derived from T
+//
+// Example:
+//   users, err := dqlx.QueryAll[User](ctx, db.QueryType("User"))
+func QueryAll[T any](ctx context.Context, query QueryBuilder, options ...OperationExecutorOptionFn) ([]T, error) {
+	var results []T
+
+	if _, err := query.UnmarshalInto(&results).Execute(ctx, options...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// QueryOne executes the query and decodes the first result into T,
+// only the first result is requested unless the query is already paginated.
+// ErrNoResults is returned when the result set is empty
+//
+// Example:
+//   user, err := dqlx.QueryOne[User](ctx, db.Query(dqlx.EqFn("email", email)))
+func QueryOne[T any](ctx context.Context, query QueryBuilder, options ...OperationExecutorOptionFn) (T, error) {
+	var result T
+
+	if !query.rootEdge.Pagination.WantsPagination() {
+		query = query.Paginate(Cursor{First: 1})
+	}
+
+	results, err := QueryAll[T](ctx, query, options...)
+
+	if err != nil {
+		return result, err
+	}
+
+	if len(results) == 0 {
+		return result, ErrNoResults
+	}
+
+	return results[0], nil
+}
+
+// Decode decodes the result set of a response into T
+//
+// Example:
+//   response, err := db.ExecuteQueries(ctx, queries)
+//   users, err := dqlx.Decode[[]User](response)
+func Decode[T any](response *Response) (T, error) {
+	var result T
+
+	if response == nil || response.Raw == nil {
+		return result, errors.New("cannot decode an empty response")
+	}
+
+	err := response.Unmarshal(&result)
+	return result, err
+}
+
+// TypedMutation a mutation setting and deleting values of type T
+type TypedMutation[T any] struct {
+	mutation MutationBuilder
+	setData  []T
+	delData  []T
+}
+
+// MutationResult the result of a typed mutation
+type MutationResult[T any] struct {
+	// UIDs assigned to blank nodes
+	UIDs map[string]string
+	// Results of the upsert query, decoded into T
+	Results []T
+	// Response the raw response
+	Response *Response
+}
+
+// MutationOf creates a typed mutation on top of a mutation builder
+//
+// Example:
+//   result, err := dqlx.MutationOf[User](db.Mutation()).Set(user).Execute(ctx)
+func MutationOf[T any](mutation MutationBuilder) TypedMutation[T] {
+	return TypedMutation[T]{mutation: mutation}
+}
+
+// Set adds values to be inserted or updated
+func (typed TypedMutation[T]) Set(data ...T) TypedMutation[T] {
+	typed.setData = append(append([]T{}, typed.setData...), data...)
+	return typed
+}
+
+// Delete adds values to be deleted
+func (typed TypedMutation[T]) Delete(data ...T) TypedMutation[T] {
+	typed.delData = append(append([]T{}, typed.delData...), data...)
+	return typed
+}
+
+// Builder returns the underlying mutation builder
+func (typed TypedMutation[T]) Builder() MutationBuilder {
+	mutation := typed.mutation
+
+	if len(typed.setData) > 0 {
+		mutation = mutation.Set(typed.setData)
+	}
+
+	if len(typed.delData) > 0 {
+		mutation = mutation.Delete(typed.delData)
+	}
+
+	return mutation
+}
+
+// Execute executes the mutation, the results of the upsert query
+// are decoded into T
+func (typed TypedMutation[T]) Execute(ctx context.Context, options ...OperationExecutorOptionFn) (MutationResult[T], error) {
+	response, err := typed.Builder().Execute(ctx, options...)
+
+	if err != nil {
+		return MutationResult[T]{}, err
+	}
+
+	return decodeMutationResult[T](response)
+}
+
+func decodeMutationResult[T any](response *Response) (MutationResult[T], error) {
+	result := MutationResult[T]{
+		UIDs:     response.Raw.GetUids(),
+		Response: response,
+	}
+
+	if response.dataKeyPath == "" || len(response.Raw.GetJson()) == 0 {
+		return result, nil
+	}
+
+	results, err := Decode[[]T](response)
+
+	if err != nil {
+		return MutationResult[T]{}, err
+	}
+
+	result.Results = results
+	return result, nil
+}
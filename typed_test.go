@@ -0,0 +1,72 @@
+package dqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v200/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+type typedUser struct {
+	UID  string `json:"uid,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func Test_Decode(t *testing.T) {
+	response := &Response{
+		Raw:         &api.Response{Json: []byte(`{"rootQuery": [{"uid": "0x1", "name": "alice"}]}`)},
+		dataKeyPath: "rootQuery",
+	}
+
+	users, err := Decode[[]typedUser](response)
+	require.NoError(t, err)
+	require.Equal(t, []typedUser{{UID: "0x1", Name: "alice"}}, users)
+
+	_, err = Decode[[]typedUser](nil)
+	require.EqualError(t, err, "cannot decode an empty response")
+}
+
+func Test_Decode_Mutation_Result(t *testing.T) {
+	response := &Response{
+		Raw: &api.Response{
+			Json: []byte(`{"users": [{"uid": "0x2", "name": "bob"}]}`),
+			Uids: map[string]string{"alice": "0x1"},
+		},
+		dataKeyPath: "users",
+	}
+
+	result, err := decodeMutationResult[typedUser](response)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"alice": "0x1"}, result.UIDs)
+	require.Equal(t, []typedUser{{UID: "0x2", Name: "bob"}}, result.Results)
+
+	// mutations without an upsert query only return uids
+	response.dataKeyPath = ""
+	result, err = decodeMutationResult[typedUser](response)
+	require.NoError(t, err)
+	require.Nil(t, result.Results)
+}
+
+func Test_Typed_Mutation(t *testing.T) {
+	alice := typedUser{UID: "_:alice", Name: "alice"}
+	bob := typedUser{UID: "0x2"}
+
+	base := MutationOf[typedUser](Mutation()).Set(alice)
+	mutation := base.Set(typedUser{UID: "_:carol"}).Delete(bob).Builder()
+
+	require.Equal(t, []typedUser{alice, {UID: "_:carol"}}, mutation.setData)
+	require.Equal(t, []typedUser{bob}, mutation.delData)
+
+	// typed mutations are immutable
+	require.Equal(t, []typedUser{alice}, base.Builder().setData)
+	require.Nil(t, base.Builder().delData)
+}
+
+func Test_Typed_Query_Requires_Client(t *testing.T) {
+	_, err := QueryAll[typedUser](context.Background(), QueryType("User"))
+	require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+
+	_, err = QueryOne[typedUser](context.Background(), QueryType("User"))
+	require.EqualError(t, err, "cannot execute query without setting a dqlx. use DClient() to set one")
+}
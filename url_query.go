@@ -0,0 +1,423 @@
+package dqlx
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URLOperator represents an operator usable in a query parameter
+//
+// Example: ?age[gt]=5
+type URLOperator string
+
+var (
+	URLOperatorEq         URLOperator = "eq"
+	URLOperatorNe         URLOperator = "ne"
+	URLOperatorGt         URLOperator = "gt"
+	URLOperatorGe         URLOperator = "ge"
+	URLOperatorLt         URLOperator = "lt"
+	URLOperatorLe         URLOperator = "le"
+	URLOperatorIn         URLOperator = "in"
+	URLOperatorHas        URLOperator = "has"
+	URLOperatorAllOfTerms URLOperator = "allofterms"
+	URLOperatorAnyOfTerms URLOperator = "anyofterms"
+)
+
+// Reserved query parameters
+const (
+	URLParamSort   = "sort"
+	URLParamLimit  = "limit"
+	URLParamOffset = "offset"
+	URLParamAfter  = "after"
+)
+
+// URLField describes a query parameter accepted by a URLQuery
+type URLField struct {
+	// Predicate filtered by the parameter, defaults to the parameter name
+	Predicate string
+	// Type of the values, used to coerce the parameter values.
+	// Defaults to ScalarString
+	Type DGraphScalar
+	// Operators allowed on the parameter, defaults to eq
+	Operators []URLOperator
+	// Sortable allows ordering by the parameter
+	Sortable bool
+}
+
+// URLQuery maps URL query parameters into filters, ordering and pagination
+// following an allowlist of fields.
+//
+// Syntax:
+//   ?age=5                 eq(age, 5)
+//   ?age[gt]=5             gt(age, 5), also ne, ge, lt, le, allofterms, anyofterms
+//   ?status[in]=a,b        eq(status, ["a","b"])
+//   ?email[has]=true       has(email)
+//   ?sort=-created_at,name orderdesc: created_at, orderasc: name
+//   ?limit=20&offset=40    first: 20, offset: 40
+//   ?after=0x1             after: 0x1
+type URLQuery struct {
+	fields       map[string]URLField
+	maxLimit     int
+	defaultLimit int
+}
+
+// URLQueryOptionFn used to modify options of the url query
+type URLQueryOptionFn func(query *URLQuery)
+
+// WithMaxLimit rejects limits greater than the given value
+func WithMaxLimit(limit int) URLQueryOptionFn {
+	return func(query *URLQuery) {
+		query.maxLimit = limit
+	}
+}
+
+// WithDefaultLimit sets the limit used when none is requested
+func WithDefaultLimit(limit int) URLQueryOptionFn {
+	return func(query *URLQuery) {
+		query.defaultLimit = limit
+	}
+}
+
+// URLParams the result of mapping URL query parameters
+type URLParams struct {
+	Filters []DQLizer
+	Order   []DQLizer
+	Cursor  Cursor
+}
+
+// URLQueryError is returned when a query parameter is not accepted
+type URLQueryError struct {
+	Param   string
+	Message string
+}
+
+// Error returns the error message together with the parameter
+func (err *URLQueryError) Error() string {
+	return fmt.Sprintf("invalid query parameter '%s': %s", err.Param, err.Message)
+}
+
+var urlParamRegex = regexp.MustCompile(`^([^\[\]]+)(?:\[([^\[\]]+)\])?$`)
+
+// NewURLQuery creates a new URLQuery accepting the given fields,
+// the map is keyed by query parameter name
+//
+// Example:
+//   dqlx.NewURLQuery(map[string]dqlx.URLField{
+//     "age":        {Type: dqlx.ScalarInt, Operators: []dqlx.URLOperator{dqlx.URLOperatorGt}},
+//     "created_at": {Type: dqlx.ScalarDateTime, Sortable: true},
+//   }, dqlx.WithMaxLimit(100))
+func NewURLQuery(fields map[string]URLField, options ...URLQueryOptionFn) URLQuery {
+	query := URLQuery{
+		fields: map[string]URLField{},
+	}
+
+	for name, field := range fields {
+		if field.Predicate == "" {
+			field.Predicate = name
+		}
+
+		if field.Type == "" {
+			field.Type = ScalarString
+		}
+
+		if len(field.Operators) == 0 {
+			field.Operators = []URLOperator{URLOperatorEq}
+		}
+
+		query.fields[name] = field
+	}
+
+	for _, option := range options {
+		option(&query)
+	}
+
+	return query
+}
+
+// Parse maps the given values, unknown parameters,
+// operators and invalid values are rejected with an *URLQueryError
+//
+// Example:
+//   params, err := urlQuery.Parse(request.URL.Query())
+//   dqlx.QueryType("User").Apply(params.Apply)
+func (query URLQuery) Parse(values url.Values) (URLParams, error) {
+	params := URLParams{}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var err error
+
+		switch key {
+		case URLParamSort:
+			params.Order, err = query.parseSort(values[key])
+		case URLParamLimit:
+			params.Cursor.First, err = query.parseLimit(key, values[key])
+		case URLParamOffset:
+			params.Cursor.Offset, err = parseURLInt(key, values[key])
+		case URLParamAfter:
+			params.Cursor.After, err = singleURLValue(key, values[key])
+		default:
+			var filters []DQLizer
+			filters, err = query.parseFilter(key, values[key])
+			params.Filters = append(params.Filters, filters...)
+		}
+
+		if err != nil {
+			return URLParams{}, err
+		}
+	}
+
+	if _, ok := values[URLParamLimit]; !ok {
+		params.Cursor.First = query.defaultLimit
+	}
+
+	return params, nil
+}
+
+// Apply applies the filters, ordering and pagination to the given query
+//
+// Example:
+//   dqlx.QueryType("User").Apply(params.Apply)
+func (params URLParams) Apply(builder QueryBuilder) QueryBuilder {
+	if len(params.Filters) > 0 {
+		builder = builder.Filter(params.Filters...)
+	}
+
+	for _, order := range params.Order {
+		builder = builder.Order(order)
+	}
+
+	if params.Cursor.WantsPagination() {
+		builder = builder.Paginate(params.Cursor)
+	}
+
+	return builder
+}
+
+func (query URLQuery) parseFilter(key string, values []string) ([]DQLizer, error) {
+	matches := urlParamRegex.FindStringSubmatch(key)
+
+	if matches == nil {
+		return nil, &URLQueryError{Param: key, Message: "malformed parameter"}
+	}
+
+	field, ok := query.fields[matches[1]]
+
+	if !ok {
+		return nil, &URLQueryError{Param: key, Message: "unknown parameter"}
+	}
+
+	operator := URLOperatorEq
+	if matches[2] != "" {
+		operator = URLOperator(strings.ToLower(matches[2]))
+	}
+
+	if !field.allows(operator) {
+		return nil, &URLQueryError{Param: key, Message: fmt.Sprintf("operator '%s' is not allowed", operator)}
+	}
+
+	filters := make([]DQLizer, 0, len(values))
+
+	for _, value := range values {
+		filter, err := field.filter(operator, value)
+
+		if err != nil {
+			return nil, &URLQueryError{Param: key, Message: err.Error()}
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func (query URLQuery) parseSort(values []string) ([]DQLizer, error) {
+	var order []DQLizer
+
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			direction := OrderDirectionAsc
+
+			switch {
+			case strings.HasPrefix(name, "-"):
+				direction = OrderDirectionDesc
+				name = name[1:]
+			case strings.HasPrefix(name, "+"):
+				name = name[1:]
+			}
+
+			field, ok := query.fields[name]
+
+			if !ok || !field.Sortable {
+				return nil, &URLQueryError{Param: URLParamSort, Message: fmt.Sprintf("cannot sort by '%s'", name)}
+			}
+
+			order = append(order, orderBy{
+				Direction: direction,
+				Predicate: field.Predicate,
+			})
+		}
+	}
+
+	return order, nil
+}
+
+func (query URLQuery) parseLimit(key string, values []string) (int, error) {
+	limit, err := parseURLInt(key, values)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// a zero limit would render a query without pagination
+	if limit < 1 {
+		return 0, &URLQueryError{Param: key, Message: "must be at least 1"}
+	}
+
+	if query.maxLimit > 0 && limit > query.maxLimit {
+		return 0, &URLQueryError{Param: key, Message: fmt.Sprintf("must be at most %d", query.maxLimit)}
+	}
+
+	return limit, nil
+}
+
+func (field URLField) allows(operator URLOperator) bool {
+	for _, allowed := range field.Operators {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (field URLField) filter(operator URLOperator, value string) (DQLizer, error) {
+	predicate := field.Predicate
+
+	switch operator {
+	case URLOperatorHas:
+		has, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, given '%s'", value)
+		}
+
+		if !has {
+			return Not{Has(predicate)}, nil
+		}
+		return Has(predicate), nil
+	case URLOperatorIn:
+		items := strings.Split(value, ",")
+		values := make([]interface{}, 0, len(items))
+
+		for _, item := range items {
+			coerced, err := coerceURLValue(field.Type, item)
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, coerced)
+		}
+
+		return Eq{predicate: values}, nil
+	case URLOperatorAllOfTerms:
+		return AllOfTerms{predicate: value}, nil
+	case URLOperatorAnyOfTerms:
+		return AnyOfTerms{predicate: value}, nil
+	}
+
+	coerced, err := coerceURLValue(field.Type, value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case URLOperatorNe:
+		return Not{Eq{predicate: coerced}}, nil
+	case URLOperatorGt:
+		return Gt{predicate: coerced}, nil
+	case URLOperatorGe:
+		return Ge{predicate: coerced}, nil
+	case URLOperatorLt:
+		return Lt{predicate: coerced}, nil
+	case URLOperatorLe:
+		return Le{predicate: coerced}, nil
+	case URLOperatorEq:
+		return Eq{predicate: coerced}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator '%s'", operator)
+}
+
+// coerceURLValue converts the value into the Go type of the scalar
+func coerceURLValue(scalar DGraphScalar, value string) (interface{}, error) {
+	switch scalar {
+	case ScalarInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, given '%s'", value)
+		}
+		return number, nil
+	case ScalarFloat:
+		number, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, given '%s'", value)
+		}
+		return number, nil
+	case ScalarBool:
+		boolean, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, given '%s'", value)
+		}
+		return boolean, nil
+	case ScalarDateTime:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date, nil
+			}
+		}
+		return nil, fmt.Errorf("expected a date, given '%s'", value)
+	case ScalarString, ScalarUID:
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("values of type '%s' cannot be filtered", scalar)
+}
+
+func singleURLValue(key string, values []string) (string, error) {
+	if len(values) != 1 {
+		return "", &URLQueryError{Param: key, Message: "expected a single value"}
+	}
+	return values[0], nil
+}
+
+func parseURLInt(key string, values []string) (int, error) {
+	value, err := singleURLValue(key, values)
+
+	if err != nil {
+		return 0, err
+	}
+
+	number, err := strconv.Atoi(value)
+
+	if err != nil || number < 0 {
+		return 0, &URLQueryError{Param: key, Message: fmt.Sprintf("expected a positive integer, given '%s'", value)}
+	}
+
+	return number, nil
+}
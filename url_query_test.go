@@ -0,0 +1,125 @@
+package dqlx_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	dql "github.com/fenos/dqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func newUsersURLQuery() dql.URLQuery {
+	return dql.NewURLQuery(map[string]dql.URLField{
+		"age": {
+			Type:      dql.ScalarInt,
+			Operators: []dql.URLOperator{dql.URLOperatorEq, dql.URLOperatorGt, dql.URLOperatorLe},
+			Sortable:  true,
+		},
+		"status": {
+			Operators: []dql.URLOperator{dql.URLOperatorEq, dql.URLOperatorNe, dql.URLOperatorIn},
+		},
+		"email": {
+			Operators: []dql.URLOperator{dql.URLOperatorHas},
+		},
+		"created_at": {
+			Predicate: "createdAt",
+			Type:      dql.ScalarDateTime,
+			Operators: []dql.URLOperator{dql.URLOperatorGt},
+			Sortable:  true,
+		},
+	}, dql.WithMaxLimit(50), dql.WithDefaultLimit(10))
+}
+
+func Test_URL_Query_Parse(t *testing.T) {
+	values, err := url.ParseQuery("age[gt]=5&status[in]=a,b&email[has]=true&created_at[gt]=2021-01-02&sort=-created_at,age&limit=20&offset=40")
+	require.NoError(t, err)
+
+	params, err := newUsersURLQuery().Parse(values)
+	require.NoError(t, err)
+
+	require.Equal(t, []dql.DQLizer{
+		dql.Gt{"age": int64(5)},
+		dql.Gt{"createdAt": time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)},
+		dql.Has("email"),
+		dql.Eq{"status": []interface{}{"a", "b"}},
+	}, params.Filters)
+
+	require.Equal(t, []dql.DQLizer{
+		dql.OrderDesc("createdAt"),
+		dql.OrderAsc("age"),
+	}, params.Order)
+
+	require.Equal(t, dql.Cursor{First: 20, Offset: 40}, params.Cursor)
+}
+
+func Test_URL_Query_Apply(t *testing.T) {
+	values, err := url.ParseQuery("age=18&age[le]=65&status[ne]=banned&sort=age")
+	require.NoError(t, err)
+
+	params, err := newUsersURLQuery().Parse(values)
+	require.NoError(t, err)
+
+	query, variables, err := dql.QueryType("User").Apply(params.Apply).ToDQL()
+	require.NoError(t, err)
+
+	expected := dql.Minify(`
+		query Rootquery($0:int, $1:int, $2:int, $3:string) {
+			<rootQuery>(func: type(<User>),first:$0,orderasc:<age>) @filter(eq(<age>,$1) AND le(<age>,$2) AND NOT (eq(<status>,$3))) {  }
+		}
+	`)
+
+	require.Equal(t, expected, dql.Minify(query))
+	require.Equal(t, map[string]string{
+		"$0": "10",
+		"$1": "18",
+		"$2": "65",
+		"$3": "banned",
+	}, variables)
+}
+
+func Test_URL_Query_Errors(t *testing.T) {
+	cases := map[string]string{
+		"password=x":        "invalid query parameter 'password': unknown parameter",
+		"age[ge]=5":         "invalid query parameter 'age[ge]': operator 'ge' is not allowed",
+		"age[gt]=old":       "invalid query parameter 'age[gt]': expected an integer, given 'old'",
+		"age[gt":            "invalid query parameter 'age[gt': malformed parameter",
+		"email[has]=maybe":  "invalid query parameter 'email[has]': expected a boolean, given 'maybe'",
+		"created_at[gt]=x":  "invalid query parameter 'created_at[gt]': expected a date, given 'x'",
+		"sort=-status":      "invalid query parameter 'sort': cannot sort by 'status'",
+		"limit=100":         "invalid query parameter 'limit': must be at most 50",
+		"limit=-1":          "invalid query parameter 'limit': expected a positive integer, given '-1'",
+		"limit=0":           "invalid query parameter 'limit': must be at least 1",
+		"offset=1&offset=2": "invalid query parameter 'offset': expected a single value",
+	}
+
+	for input, expected := range cases {
+		t.Run(input, func(t *testing.T) {
+			values, err := url.ParseQuery(input)
+			require.NoError(t, err)
+
+			_, err = newUsersURLQuery().Parse(values)
+
+			var urlError *dql.URLQueryError
+			require.True(t, errors.As(err, &urlError))
+			require.EqualError(t, err, expected)
+		})
+	}
+}
+
+func Test_URL_Query_Zero_Limit(t *testing.T) {
+	// queries without a maximum limit cannot be unpaginated either
+	query := dql.NewURLQuery(map[string]dql.URLField{}, dql.WithDefaultLimit(10))
+
+	_, err := query.Parse(url.Values{"limit": {"0"}})
+	require.EqualError(t, err, "invalid query parameter 'limit': must be at least 1")
+
+	params, err := query.Parse(url.Values{})
+	require.NoError(t, err)
+
+	dqlQuery, variables, err := dql.QueryType("User").Apply(params.Apply).ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, dqlQuery, "first:$0")
+	require.Equal(t, map[string]string{"$0": "10"}, variables)
+}
@@ -0,0 +1,242 @@
+package dqlx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dqlRenderer is implemented by the parts able to write
+// their statement directly into a dqlWriter
+type dqlRenderer interface {
+	renderDQL(writer *dqlWriter) error
+}
+
+// bindMode defines how values are written
+type bindMode uint8
+
+const (
+	// bindVariables writes GraphQL variables: $0, $1...
+	bindVariables bindMode = iota
+	// bindPlaceholders writes ?? placeholders, used to implement ToDQL
+	bindPlaceholders
+	// bindLiterals writes values as literals, used by mutation conditions
+	bindLiterals
+)
+
+// dqlWriter renders a document in a single pass into one buffer.
+// Values are bound while writing: each value gets the index it has in the
+// final list of arguments, so the rendered document is never scanned
+// and raw expressions can't be confused with bound values
+type dqlWriter struct {
+	buffer bytes.Buffer
+	args   []interface{}
+	mode   bindMode
+	err    error
+}
+
+// writerMark a position of the writer, used to roll back empty statements
+type writerMark struct {
+	length int
+	args   int
+}
+
+// renderToDQL renders a part the way ToDQL returns it,
+// with ?? placeholders and their arguments
+func renderToDQL(renderer dqlRenderer) (query string, args []interface{}, err error) {
+	writer := &dqlWriter{mode: bindPlaceholders}
+
+	if err := renderer.renderDQL(writer); err != nil {
+		return "", nil, err
+	}
+
+	return writer.String(), writer.args, nil
+}
+
+// String returns the rendered statement
+func (writer *dqlWriter) String() string {
+	return writer.buffer.String()
+}
+
+// WriteString writes a raw statement
+func (writer *dqlWriter) WriteString(statement string) {
+	writer.buffer.WriteString(statement)
+}
+
+func (writer *dqlWriter) mark() writerMark {
+	return writerMark{length: writer.buffer.Len(), args: len(writer.args)}
+}
+
+// wroteSince determines if anything was written after the mark
+func (writer *dqlWriter) wroteSince(mark writerMark) bool {
+	return writer.buffer.Len() > mark.length
+}
+
+// reset discards what was written after the mark
+func (writer *dqlWriter) reset(mark writerMark) {
+	writer.buffer.Truncate(mark.length)
+	writer.args = writer.args[:mark.args]
+}
+
+// bind writes a bound value
+func (writer *dqlWriter) bind(value interface{}) {
+	switch writer.mode {
+	case bindPlaceholders:
+		writer.buffer.WriteString(symbolValuePlaceholder)
+	case bindLiterals:
+		literal, err := toConditionLiteral(value)
+
+		if err != nil && writer.err == nil {
+			writer.err = err
+		}
+
+		writer.buffer.WriteString(literal)
+	default:
+		var digits [20]byte
+
+		writer.buffer.WriteByte('$')
+		writer.buffer.Write(strconv.AppendInt(digits[:0], int64(len(writer.args)), 10))
+	}
+
+	writer.args = append(writer.args, value)
+}
+
+// writeValue writes the value of a function: raw expressions are written
+// as they are, lists and other values are bound
+func (writer *dqlWriter) writeValue(value interface{}) error {
+	if isListType(value) {
+		values, err := toInterfaceSlice(value)
+
+		if err != nil {
+			return err
+		}
+
+		writer.buffer.WriteByte('[')
+
+		for index, item := range values {
+			if index > 0 {
+				writer.buffer.WriteByte(',')
+			}
+
+			writer.bind(item)
+		}
+
+		writer.buffer.WriteByte(']')
+		return nil
+	}
+
+	if expression, ok := value.(RawExpression); ok {
+		writer.buffer.WriteString(expression.Val)
+		return nil
+	}
+
+	writer.bind(value)
+	return nil
+}
+
+// writePart writes a part, parts not implementing dqlRenderer
+// are rendered with ToDQL and their placeholders are bound
+func (writer *dqlWriter) writePart(part DQLizer) error {
+	if renderer, ok := toRenderer(part); ok {
+		return renderer.renderDQL(writer)
+	}
+
+	statement, args, err := part.ToDQL()
+
+	if err != nil {
+		return err
+	}
+
+	return writer.writeStatement(statement, args)
+}
+
+// writeParts writes the parts joined by the separator
+func (writer *dqlWriter) writeParts(parts []DQLizer, separator string) error {
+	for index, part := range parts {
+		if index > 0 {
+			writer.buffer.WriteString(separator)
+		}
+
+		if err := writer.writePart(part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeStatement writes a statement returned by ToDQL,
+// binding its placeholders to the arguments.
+// Statements without arguments are written as they are.
+// A raw ?? cannot be told apart from a placeholder, statements
+// where the counts don't match are rejected
+func (writer *dqlWriter) writeStatement(statement string, args []interface{}) error {
+	if len(args) == 0 {
+		writer.buffer.WriteString(statement)
+		return nil
+	}
+
+	if placeholders := strings.Count(statement, symbolValuePlaceholder); placeholders != len(args) {
+		return fmt.Errorf("the statement '%s' has %d placeholders for %d arguments, raw expressions containing '??' cannot be bound", statement, placeholders, len(args))
+	}
+
+	for _, arg := range args {
+		position := strings.Index(statement, symbolValuePlaceholder)
+
+		writer.buffer.WriteString(statement[:position])
+		writer.bind(arg)
+		statement = statement[position+len(symbolValuePlaceholder):]
+	}
+
+	writer.buffer.WriteString(statement)
+	return nil
+}
+
+// toRenderer returns the renderer of the built-in expressions
+func toRenderer(part DQLizer) (dqlRenderer, bool) {
+	switch cast := part.(type) {
+	case dqlRenderer:
+		return cast, true
+	case *FilterFn:
+		return toRenderer(cast.DQLizer)
+	case FilterFn:
+		return toRenderer(cast.DQLizer)
+	case Eq:
+		return filterExpr{funcType: eqFunc, value: filterKV(cast)}, true
+	case Le:
+		return filterExpr{funcType: leFunc, value: filterKV(cast)}, true
+	case Lt:
+		return filterExpr{funcType: ltFunc, value: filterKV(cast)}, true
+	case Ge:
+		return filterExpr{funcType: geFunc, value: filterKV(cast)}, true
+	case Gt:
+		return filterExpr{funcType: gtFunc, value: filterKV(cast)}, true
+	case AllOfTerms:
+		return filterExpr{funcType: alloftermsFunc, value: filterKV(cast)}, true
+	case AnyOfTerms:
+		return filterExpr{funcType: anyoftermsFunc, value: filterKV(cast)}, true
+	case Match:
+		return filterExpr{funcType: matchFunc, value: filterKV(cast)}, true
+	case AllOfText:
+		return filterExpr{funcType: alloftextFunc, value: filterKV(cast)}, true
+	case AnyOfText:
+		return filterExpr{funcType: anyoftextFunc, value: filterKV(cast)}, true
+	case Exact:
+		return filterExpr{funcType: exactFunc, value: filterKV(cast)}, true
+	case Term:
+		return filterExpr{funcType: termFunc, value: filterKV(cast)}, true
+	case FullText:
+		return filterExpr{funcType: fulltextFunc, value: filterKV(cast)}, true
+	case UIDIn:
+		return filterExpr{funcType: uidInFunc, value: filterKV(cast)}, true
+	case Regexp:
+		patterns := filterKV{}
+		for predicate, pattern := range cast {
+			patterns[predicate] = Expr(pattern)
+		}
+		return filterExpr{funcType: regexpFunc, value: patterns}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,97 @@
+package dqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type customFilter struct{}
+
+func (customFilter) ToDQL() (query string, args []interface{}, err error) {
+	return "(eq(<a>,??) OR eq(<b>,??))", []interface{}{"a", 2}, nil
+}
+
+func TestWriterBindsCustomParts(t *testing.T) {
+	query, variables, err := QueryType("User").
+		Paginate(Cursor{First: 10, Offset: 5}).
+		Filter(customFilter{}).
+		Edge("friends", Select("name"), Eq{"name": "c"}).
+		ToDQL()
+
+	require.NoError(t, err)
+	require.Equal(t, "query Rootquery($0:int, $1:int, $2:string, $3:int, $4:string) { <rootQuery>(func: type(<User>),first:$0,offset:$1) @filter((eq(<a>,$2) OR eq(<b>,$3))) { <friends> @filter(eq(<name>,$4)) { <name> } } }", query)
+	require.Equal(t, map[string]string{"$0": "10", "$1": "5", "$2": "a", "$3": "2", "$4": "c"}, variables)
+
+	statement, args, err := QueryType("User").Paginate(Cursor{First: 10}).Filter(customFilter{}).rootEdge.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, "<rootQuery>(func: type(<User>),first:??) @filter((eq(<a>,??) OR eq(<b>,??))) {  }", statement)
+	require.Equal(t, []interface{}{10, "a", 2}, args)
+}
+
+func TestEscapePlainPredicate(t *testing.T) {
+	for _, predicate := range []string{"name", "dgraph.type", "~director.film", "first-name", "Name_2"} {
+		require.True(t, isPlainPredicate(predicate))
+		require.Equal(t, "<"+predicate+">", EscapePredicate(predicate))
+	}
+
+	for _, predicate := range []string{"", "name@en", "alias:name", "a b", "expand(_all_)", "n<a>"} {
+		require.False(t, isPlainPredicate(predicate))
+	}
+}
+
+type mismatchedFilter struct{}
+
+func (mismatchedFilter) ToDQL() (query string, args []interface{}, err error) {
+	return "eq(<a>,??) AND eq(<b>,??)", []interface{}{"a"}, nil
+}
+
+func TestWriterRawExpressionsAreNotBound(t *testing.T) {
+	query, variables, err := QueryType("User").
+		Filter(
+			Regexp{"name": "/^a??b$/"},
+			Eq{"nick": Expr("val(??)")},
+			Eq{"age": 20},
+		).
+		Select(Alias("raw", Expr("math(??)"))).
+		ToDQL()
+
+	require.NoError(t, err)
+	require.Equal(t, "query Rootquery($0:int) { <rootQuery>(func: type(<User>)) @filter(regexp(<name>,/^a??b$/) AND eq(<nick>,val(??)) AND eq(<age>,$0)) { <raw>:math(??) } }", query)
+	require.Equal(t, map[string]string{"$0": "20"}, variables)
+}
+
+func TestWriterPlaceholderMismatch(t *testing.T) {
+	_, _, err := QueryType("User").Filter(mismatchedFilter{}).ToDQL()
+	require.EqualError(t, err, "the statement 'eq(<a>,??) AND eq(<b>,??)' has 2 placeholders for 1 arguments, raw expressions containing '??' cannot be bound")
+
+	_, _, err = Condition(mismatchedFilter{}).ToDQL()
+	require.Error(t, err)
+}
+
+type wrappedFilter struct {
+	DQLizer
+}
+
+func TestWriterCustomPartWithRawPlaceholder(t *testing.T) {
+	filter := Eq{"y": Expr("??"), "z": 2}
+
+	// the output of ToDQL doesn't tell the raw ?? from the placeholder
+	statement, args, err := filter.ToDQL()
+	require.NoError(t, err)
+	require.Equal(t, "eq(<y>,??) AND eq(<z>,??)", statement)
+	require.Equal(t, []interface{}{2}, args)
+
+	query, _, err := QueryType("User").Filter(filter).ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, "@filter(eq(<y>,??) AND eq(<z>,$0))")
+
+	// custom parts are rendered from their ToDQL output
+	_, _, err = QueryType("User").Filter(wrappedFilter{filter}).ToDQL()
+	require.EqualError(t, err, "the statement 'eq(<y>,??) AND eq(<z>,??)' has 2 placeholders for 1 arguments, raw expressions containing '??' cannot be bound")
+
+	// without arguments the statement is written as it is
+	query, _, err = QueryType("User").Filter(wrappedFilter{Eq{"y": Expr("??")}}).ToDQL()
+	require.NoError(t, err)
+	require.Contains(t, query, "@filter(eq(<y>,??))")
+}